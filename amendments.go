@@ -0,0 +1,167 @@
+package gosunlight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var amendmentAPIS struct {
+	get     sunlightAPI
+	getList sunlightAPI
+	search  sunlightAPI
+}
+
+func init() {
+	amendmentAPIS.get = newSunlightAPI("amendments", "get")
+	amendmentAPIS.getList = newSunlightAPI("amendments", "getList")
+	amendmentAPIS.search = newSunlightAPI("amendments", "search")
+}
+
+// Amendment represents an amendment to a bill from the sunlight api.
+type Amendment struct {
+	AmendmentID       string        `json:"amendment_id"`
+	AmendmentType     string        `json:"amendment_type"`
+	Number            int           `json:"number"`
+	Congress          int           `json:"congress"`
+	Chamber           string        `json:"chamber"`
+	Purpose           string        `json:"purpose"`
+	Description       string        `json:"description"`
+	IntroducedOn      string        `json:"introduced_on"`
+	LastActionAt      string        `json:"last_action_at"`
+	AmendsBillID      string        `json:"amends_bill_id"`
+	AmendsAmendmentID string        `json:"amends_amendment_id"`
+	Actions           []*BillAction `json:"actions"`
+
+	// SponsorType is "person" for amendments sponsored by a legislator,
+	// or "committee" for those offered by a committee.
+	SponsorType string `json:"sponsor_type"`
+	SponsorID   string `json:"sponsor_id"`
+
+	// Status is "offered", "pending", "passed", "failed" or "withdrawn".
+	Status string `json:"status"`
+}
+
+// String implements fmt.Stringer for amendments
+func (a Amendment) String() string {
+	purpose := a.Purpose
+	if purpose == "" {
+		purpose = a.Description
+	}
+	return fmt.Sprintf("%v to %v %v", a.AmendmentID, a.AmendsBillID, purpose)
+}
+
+// AmendmentFilter limits the amendments returned by AmendmentGetList and
+// AmendmentSearch.  Fields left empty are not used.
+type AmendmentFilter struct {
+	// SponsorID is the Bioguide ID of the sponsoring legislator.
+	SponsorID string `param:"sponsor_id"`
+	// AmendsBillID is the id of the bill being amended.
+	AmendsBillID string `param:"amends_bill_id"`
+	Chamber      string `param:"chamber"`
+	Congress     int    `param:"congress"`
+}
+
+// AmendmentGet returns a single amendment, including its actions, given
+// an amendment id such as "samdt5-113".
+//
+// See: http://services.sunlightlabs.com/docs/congressapi/amendments.get/
+func AmendmentGet(amendmentID string) (*Amendment, error) {
+	var response amendmentResponse
+	p := params{"amendment_id": amendmentID}
+	err := amendmentAPIS.get.get(&response, p)
+	if err != nil {
+		return nil, err
+	}
+	if response.Response.Amendment == nil {
+		return nil, fmt.Errorf("Amendment %v not found", amendmentID)
+	}
+	return response.Response.Amendment, nil
+}
+
+// AmendmentGetList returns the first page of amendments matching a
+// filter.  Use AmendmentGetListIter for every page.
+//
+// See: http://services.sunlightlabs.com/docs/congressapi/amendments.getList/
+func AmendmentGetList(filter AmendmentFilter) ([]*Amendment, error) {
+	return collectList(context.Background(), amendmentAPIS.getList, "amendments", (*amendmentItem).amendment, filter)
+}
+
+// AmendmentSearch performs a full text search of amendments for a keyword
+// or phrase, returning the first page of amendments that also match a
+// filter.  Use AmendmentSearchIter for every page.
+//
+// See: http://services.sunlightlabs.com/docs/congressapi/amendments.search/
+func AmendmentSearch(query string, filter AmendmentFilter) ([]*Amendment, error) {
+	p := params{"query": query}
+	return collectList(context.Background(), amendmentAPIS.search, "amendments", (*amendmentItem).amendment, p, filter)
+}
+
+// Sponsor returns the legislator who sponsored the amendment.  Amendments
+// offered by a committee have no sponsoring legislator.  The first call
+// will block while the legislator is fetched from sunlight.  Subsequent
+// calls return a cached value.
+func (a *Amendment) Sponsor() (*Legislator, error) {
+	if a.SponsorType == "committee" {
+		return nil, fmt.Errorf("Amendment %v was offered by committee %v", a.AmendmentID, a.SponsorID)
+	}
+	if a.SponsorID == "" {
+		return nil, errors.New("SponsorID missing for amendment.")
+	}
+	return legislatorByID(a.SponsorID)
+}
+
+// Bill returns the bill the amendment amends.  The first call will block
+// while the bill is fetched from sunlight.  Subsequent calls return a
+// cached value.
+func (a *Amendment) Bill() (*Bill, error) {
+	if a.AmendsBillID == "" {
+		return nil, errors.New("AmendsBillID missing for amendment.")
+	}
+	return billsByID.get(a.AmendsBillID, func() (*Bill, error) {
+		return BillGet(a.AmendsBillID)
+	})
+}
+
+// Amendments returns every amendment offered to the bill.  The first call
+// will block while each page of amendments is fetched from sunlight.
+// Subsequent calls return a cached list.
+func (b *Bill) Amendments() ([]*Amendment, error) {
+	if b.BillID == "" {
+		return nil, errors.New("BillID missing for bill.")
+	}
+	return billAmendments.get(b.BillID, func() ([]*Amendment, error) {
+		return collect(AmendmentGetListIter(context.Background(), AmendmentFilter{AmendsBillID: b.BillID}))
+	})
+}
+
+// Amendments returns every amendment this legislator has sponsored.  The
+// first call will block while each page of amendments is fetched from
+// sunlight.  Subsequent calls return a cached list.
+func (l *Legislator) Amendments() ([]*Amendment, error) {
+	if l.BioguideID == "" {
+		return nil, errors.New("BioguideId missing for legislator.")
+	}
+	return legislatorAmendments.get(l.BioguideID, func() ([]*Amendment, error) {
+		return collect(AmendmentGetListIter(context.Background(), AmendmentFilter{SponsorID: l.BioguideID}))
+	})
+}
+
+type amendmentResponse struct {
+	Response struct {
+		Amendment *Amendment
+	}
+}
+
+// amendmentItem is an element of a list of amendments.
+type amendmentItem struct {
+	Amendment *Amendment
+}
+
+func (i *amendmentItem) amendment() *Amendment {
+	return i.Amendment
+}
+
+func (i *amendmentItem) into(a *Amendment) {
+	i.Amendment = a
+}
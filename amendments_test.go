@@ -0,0 +1,90 @@
+package gosunlight
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const amendmentsJSON = `{"response": {"amendments": [{"amendment": {
+	"amendment_id": "samdt2786-111", "amendment_type": "samdt", "number": 2786, "congress": 111,
+	"chamber": "senate", "purpose": "In the nature of a substitute.", "status": "passed",
+	"sponsor_type": "person", "sponsor_id": "R000146", "amends_bill_id": "hr3590-111",
+	"actions": [{"acted_at": "2009-12-24", "type": "vote", "result": "pass"}]
+}}]}}`
+
+func TestAmendmentDecoding(t *testing.T) {
+	serve(t, func(req *Request) (string, error) { return amendmentsJSON, nil })
+	amendments, err := AmendmentGetList(AmendmentFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(amendments) != 1 {
+		t.Fatalf("got %v amendments, want 1", len(amendments))
+	}
+	a := amendments[0]
+	if a.String() != "samdt2786-111 to hr3590-111 In the nature of a substitute." || a.Number != 2786 || a.Status != "passed" {
+		t.Errorf("unexpected amendment %+v", a)
+	}
+	if len(a.Actions) != 1 || a.Actions[0].Result != "pass" {
+		t.Errorf("unexpected actions %v", a.Actions)
+	}
+}
+
+func TestAmendmentCommitteeSponsor(t *testing.T) {
+	a := &Amendment{AmendmentID: "hamdt1-113", SponsorType: "committee", SponsorID: "HSRU"}
+	if _, err := a.Sponsor(); err == nil {
+		t.Error("expected an error for a committee sponsored amendment")
+	}
+}
+
+func TestAmendmentFilter(t *testing.T) {
+	query := url.Values{}
+	AmendmentFilter{AmendsBillID: "hr3590-111", Chamber: "senate"}.addTo(&query)
+	if query.Encode() != "amends_bill_id=hr3590-111&chamber=senate" {
+		t.Errorf("unexpected query %v", query.Encode())
+	}
+}
+
+// amendmentPages serves n amendments sponsored by S000001, PerPage at a
+// time.
+func amendmentPages(t *testing.T, n int) {
+	serve(t, func(req *Request) (string, error) {
+		if req.Method != "getList" || req.Params.Get("sponsor_id") != "S000001" {
+			return "", errors.New("Unexpected request")
+		}
+		page, _ := strconv.Atoi(req.Params.Get("page"))
+		var items []string
+		for i := (page - 1) * PerPage; i < page*PerPage && i < n; i++ {
+			items = append(items, fmt.Sprintf(`{"amendment": {"amendment_id": "samdt%v-113"}}`, i))
+		}
+		return `{"response": {"amendments": [` + strings.Join(items, ",") + `]}}`, nil
+	})
+}
+
+func TestLegislatorAmendmentsPages(t *testing.T) {
+	withPerPage(t, 2)
+	amendmentPages(t, 5)
+	amendments, err := (&Legislator{BioguideID: "S000001"}).Amendments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(amendments) != 5 || amendments[4].AmendmentID != "samdt4-113" {
+		t.Errorf("got %v amendments, want all 5", len(amendments))
+	}
+}
+
+func TestAmendmentSearch(t *testing.T) {
+	var seen []*Request
+	withMiddlewares(t, answer(200, amendmentsJSON, &seen))
+	amendments, err := AmendmentSearch("substitute", AmendmentFilter{Chamber: "senate"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(amendments) != 1 || seen[0].Method != "search" || seen[0].Params.Encode() != "chamber=senate&query=substitute" {
+		t.Errorf("got %v from request %+v", amendments, seen[0])
+	}
+}
@@ -0,0 +1,434 @@
+package gosunlight
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// BatchColumns names the input columns a BatchProcessor reads.  Each row is
+// matched using its latitude and longitude if present, then its address,
+// then its zip code.  Columns left blank are not used.
+type BatchColumns struct {
+	Zip       string
+	Latitude  string
+	Longitude string
+	Address   string
+}
+
+// BatchOutputColumns are the columns a BatchProcessor appends to each row.
+// Rows in more than one district list each district, representative and
+// senator separated by semicolons.
+var BatchOutputColumns = []string{
+	"district",
+	"representative",
+	"representative_bioguide_id",
+	"senators",
+	"senator_bioguide_ids",
+	"geocode_quality",
+	"error",
+}
+
+// BatchStats summarizes a run of BatchProcessor.Process.
+type BatchStats struct {
+	// Rows is the number of rows written during this run.
+	Rows int
+	// Skipped is the number of rows skipped because a previous run had
+	// already written them.
+	Skipped int
+	// Errors is the number of rows written with an error.
+	Errors int
+	// Lookups is the number of distinct zip codes, points and addresses
+	// looked up.
+	Lookups int
+}
+
+// BatchProcessor appends the district, representative and senators to
+// each row of a CSV file of constituents.  Rows are streamed, so files of
+// any size can be processed; repeated zip codes, points and addresses are
+// only looked up once while they are among the last LookupCacheSize
+// distinct lookups.
+type BatchProcessor struct {
+	Columns BatchColumns
+
+	// Geocoder is used for rows matched by address.  It is required if
+	// Columns.Address is set.  The quality of each geocoded match is
+	// written to the geocode_quality column.
+	Geocoder Geocoder
+
+	// Concurrency is the number of rows looked up at once.  Defaults to 8.
+	Concurrency int
+
+	// LookupCacheSize is the number of distinct lookups remembered, least
+	// recently used first to be forgotten.  Defaults to 10000.
+	LookupCacheSize int
+
+	// ProgressFile, if set, records the number of rows written and the
+	// size of the output they fill.  If the file exists when Process
+	// starts, that many input rows are skipped and no header is written,
+	// so an interrupted run can be resumed into the same output file.  The
+	// output is truncated to the recorded size first, dropping any rows
+	// written after the last progress update, so the output must then be
+	// a file, or another writer with Truncate and Seek methods.
+	ProgressFile string
+
+	lookup func(key batchKey) ([]*Delegation, error)
+}
+
+// progressInterval is the number of rows written between progress updates.
+const progressInterval = 100
+
+type batchKey struct {
+	kind  string
+	value string
+}
+
+// batchResult is the result of a lookup.  Failed lookups are cached as
+// results too, so that they are not repeated for every row.
+type batchResult struct {
+	delegations []*Delegation
+	geocode     *GeocodeResult
+	err         error
+}
+
+type batchRow struct {
+	record []string
+	done   chan struct{}
+	batchResult
+}
+
+// truncater is an output that can be cut back to the last checkpoint when
+// resuming.
+type truncater interface {
+	Truncate(size int64) error
+	Seek(offset int64, whence int) (int64, error)
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Process reads constituents from in and writes them, with the district
+// and legislator columns appended, to out.  Errors looking up individual
+// rows are reported in the row's error column; Process itself only fails
+// if the input cannot be read or the output cannot be written.
+func (b *BatchProcessor) Process(in io.Reader, out io.Writer) (*BatchStats, error) {
+	stats := &BatchStats{}
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return stats, err
+	}
+	columns, err := b.columnIndexes(header)
+	if err != nil {
+		return stats, err
+	}
+
+	done, size, err := b.readProgress()
+	if err != nil {
+		return stats, err
+	}
+	if size >= 0 {
+		output, ok := out.(truncater)
+		if !ok {
+			return stats, fmt.Errorf("Cannot resume from %v: output cannot be truncated to the last checkpoint", b.ProgressFile)
+		}
+		if err := output.Truncate(size); err != nil {
+			return stats, err
+		}
+		if _, err := output.Seek(size, io.SeekStart); err != nil {
+			return stats, err
+		}
+	}
+	counter := &countingWriter{w: out, n: max(size, 0)}
+	writer := csv.NewWriter(counter)
+	if size < 0 {
+		if err := writer.Write(append(header, BatchOutputColumns...)); err != nil {
+			return stats, err
+		}
+	}
+	for ; stats.Skipped < done; stats.Skipped++ {
+		if _, err := reader.Read(); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return stats, err
+		}
+	}
+
+	concurrency := b.Concurrency
+	if concurrency <= 0 {
+		concurrency = 8
+	}
+	results := cache[batchKey, batchResult]{size: b.LookupCacheSize}
+	if results.size <= 0 {
+		results.size = 10000
+	}
+	var lookups int32
+	lookup := func(key batchKey, address string) batchResult {
+		result, _ := results.get(key, func() (batchResult, error) {
+			atomic.AddInt32(&lookups, 1)
+			return b.lookupKey(key, address), nil
+		})
+		return result
+	}
+
+	// Rows are looked up concurrently but written in order: the writer
+	// waits on each pending row in the order it was read.
+	pending := make(chan *batchRow, concurrency*2)
+	sem := make(chan struct{}, concurrency)
+	writeErr := make(chan error, 1)
+	stop := make(chan struct{})
+	go func() {
+		var err error
+		for row := range pending {
+			<-row.done
+			if err != nil {
+				continue
+			}
+			err = b.writeRow(writer, row, stats)
+			if err == nil && stats.Rows%progressInterval == 0 {
+				err = b.writeProgress(writer, counter, done+stats.Rows)
+			}
+			if err != nil {
+				close(stop)
+			}
+		}
+		if err == nil {
+			err = b.writeProgress(writer, counter, done+stats.Rows)
+		}
+		writeErr <- err
+	}()
+
+	var readErr error
+read:
+	for {
+		select {
+		case <-stop:
+			break read
+		default:
+		}
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			readErr = err
+			break
+		}
+		row := &batchRow{record: record, done: make(chan struct{})}
+		pending <- row
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem }()
+			defer close(row.done)
+			key, address, err := b.rowKey(record, columns)
+			if err != nil {
+				row.err = err
+				return
+			}
+			row.batchResult = lookup(key, address)
+		}()
+	}
+	close(pending)
+
+	err = <-writeErr
+	stats.Lookups = int(atomic.LoadInt32(&lookups))
+	if err != nil {
+		return stats, err
+	}
+	return stats, readErr
+}
+
+// columnIndexes finds the configured columns in the header row.
+func (b *BatchProcessor) columnIndexes(header []string) (map[string]int, error) {
+	indexes := make(map[string]int)
+	for _, name := range []string{b.Columns.Zip, b.Columns.Latitude, b.Columns.Longitude, b.Columns.Address} {
+		if name == "" {
+			continue
+		}
+		found := false
+		for i, h := range header {
+			if strings.TrimSpace(h) == name {
+				indexes[name] = i
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("Column %q missing from input", name)
+		}
+	}
+	if len(indexes) == 0 {
+		return nil, errors.New("No input columns configured for batch processing")
+	}
+	if (b.Columns.Latitude == "") != (b.Columns.Longitude == "") {
+		return nil, errors.New("Latitude and longitude columns must be configured together")
+	}
+	if b.Columns.Address != "" && b.Geocoder == nil {
+		return nil, errors.New("A geocoder is required to process addresses")
+	}
+	return indexes, nil
+}
+
+// rowKey picks the lookup for a row: coordinates, then address, then zip.
+// Addresses are keyed in normalized form, so that rows differing only in
+// case or punctuation share a lookup, and the address is also returned as
+// written, to be sent to the geocoder.
+func (b *BatchProcessor) rowKey(record []string, columns map[string]int) (batchKey, string, error) {
+	field := func(name string) string {
+		i, ok := columns[name]
+		if !ok || name == "" || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	if lat, long := field(b.Columns.Latitude), field(b.Columns.Longitude); lat != "" && long != "" {
+		latitude, err := strconv.ParseFloat(lat, 64)
+		if err != nil {
+			return batchKey{}, "", fmt.Errorf("Invalid latitude %q", lat)
+		}
+		longitude, err := strconv.ParseFloat(long, 64)
+		if err != nil {
+			return batchKey{}, "", fmt.Errorf("Invalid longitude %q", long)
+		}
+		return pointKey(latitude, longitude), "", nil
+	}
+	if address := field(b.Columns.Address); address != "" {
+		return batchKey{"address", normalizeAddress(address)}, address, nil
+	}
+	if zip := field(b.Columns.Zip); zip != "" {
+		return batchKey{"zip", zip}, "", nil
+	}
+	return batchKey{}, "", errors.New("Row has no zip code, coordinates or address")
+}
+
+func pointKey(latitude, longitude float64) batchKey {
+	return batchKey{"point", fmt.Sprintf("%.6f,%.6f", latitude, longitude)}
+}
+
+// lookupKey geocodes the address of an address key, and fetches the
+// delegations for a key from sunlight.
+func (b *BatchProcessor) lookupKey(key batchKey, address string) batchResult {
+	if key.kind == "address" {
+		geocode, err := b.Geocoder.Geocode(address)
+		if err != nil {
+			return batchResult{geocode: geocode, err: err}
+		}
+		result := b.lookupKey(pointKey(geocode.Latitude, geocode.Longitude), "")
+		result.geocode = geocode
+		return result
+	}
+	var result batchResult
+	result.delegations, result.err = b.delegations(key)
+	return result
+}
+
+// delegations fetches the delegations for a zip code or point key.
+func (b *BatchProcessor) delegations(key batchKey) ([]*Delegation, error) {
+	if b.lookup != nil {
+		return b.lookup(key)
+	}
+	if key.kind == "zip" {
+		return DelegationsForZip(key.value)
+	}
+	var latitude, longitude float64
+	if _, err := fmt.Sscanf(key.value, "%f,%f", &latitude, &longitude); err != nil {
+		return nil, err
+	}
+	delegation, err := DelegationForLatLong(latitude, longitude)
+	if err != nil {
+		return nil, err
+	}
+	return []*Delegation{delegation}, nil
+}
+
+// writeRow appends the lookup results to a row and writes it.
+func (b *BatchProcessor) writeRow(writer *csv.Writer, row *batchRow, stats *BatchStats) error {
+	var districts, reps, repIDs, senators, senatorIDs, quality, errorText []string
+	seenSenators := make(map[string]bool)
+	for _, d := range row.delegations {
+		districts = append(districts, d.District.String())
+		if d.Representative != nil {
+			reps = append(reps, d.Representative.String())
+			repIDs = append(repIDs, d.Representative.BioguideID)
+		}
+		for _, s := range d.Senators {
+			if !seenSenators[s.BioguideID] {
+				seenSenators[s.BioguideID] = true
+				senators = append(senators, s.String())
+				senatorIDs = append(senatorIDs, s.BioguideID)
+			}
+		}
+	}
+	if row.geocode != nil {
+		quality = append(quality, row.geocode.Quality.String())
+	}
+	if row.err != nil {
+		errorText = append(errorText, row.err.Error())
+		stats.Errors++
+	}
+
+	record := row.record
+	for _, column := range [][]string{districts, reps, repIDs, senators, senatorIDs, quality, errorText} {
+		record = append(record, strings.Join(column, ";"))
+	}
+	stats.Rows++
+	return writer.Write(record)
+}
+
+// readProgress returns the number of rows written by a previous run, and
+// the size of the output they filled, which is -1 when there is no
+// previous run.
+func (b *BatchProcessor) readProgress() (int, int64, error) {
+	if b.ProgressFile == "" {
+		return 0, -1, nil
+	}
+	data, err := os.ReadFile(b.ProgressFile)
+	if os.IsNotExist(err) {
+		return 0, -1, nil
+	}
+	if err != nil {
+		return 0, -1, err
+	}
+	var done int
+	var size int64
+	if _, err := fmt.Sscanf(string(data), "%d %d", &done, &size); err != nil {
+		return 0, -1, fmt.Errorf("Invalid progress file %v: %v", b.ProgressFile, err)
+	}
+	return done, size, nil
+}
+
+// writeProgress flushes the output and then records the number of rows
+// written and the size of the output.  The progress file is replaced
+// atomically, so it always matches a flushed checkpoint of the output.
+func (b *BatchProcessor) writeProgress(writer *csv.Writer, counter *countingWriter, done int) error {
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	if b.ProgressFile == "" {
+		return nil
+	}
+	tmp := b.ProgressFile + ".tmp"
+	if err := os.WriteFile(tmp, []byte(fmt.Sprintf("%d %d\n", done, counter.n)), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, b.ProgressFile)
+}
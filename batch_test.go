@@ -0,0 +1,210 @@
+package gosunlight
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const batchInput = `name,zip,lat,long
+Alice,12345,,
+Bob,12345,,
+Carol,,35.778788,-78.787805
+Dave,99999,,
+Eve,,,
+`
+
+func testBatchProcessor() (*BatchProcessor, *int) {
+	var lock sync.Mutex
+	calls := 0
+	return &BatchProcessor{
+		Columns:     BatchColumns{Zip: "zip", Latitude: "lat", Longitude: "long"},
+		Concurrency: 3,
+		lookup: func(key batchKey) ([]*Delegation, error) {
+			lock.Lock()
+			calls++
+			lock.Unlock()
+			if key.value == "99999" {
+				return nil, errors.New("no such zip")
+			}
+			district := &District{State: "NC", Number: "4"}
+			return []*Delegation{{
+				State:          "NC",
+				District:       district,
+				Representative: &Legislator{Title: "Rep", LastName: "Price", Party: "D", State: "NC", BioguideID: "P000523"},
+				Senators: []*Legislator{
+					{Title: "Sen", LastName: "Burr", Party: "R", State: "NC", BioguideID: "B001135"},
+					{Title: "Sen", LastName: "Hagan", Party: "D", State: "NC", BioguideID: "H001049"},
+				},
+			}}, nil
+		},
+	}, &calls
+}
+
+func TestBatchProcessor(t *testing.T) {
+	b, calls := testBatchProcessor()
+	var out bytes.Buffer
+	stats, err := b.Process(strings.NewReader(batchInput), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Rows != 5 || stats.Errors != 2 || stats.Lookups != 3 || *calls != 3 {
+		t.Errorf("unexpected stats %+v with %v calls", stats, *calls)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("got %v lines, want 6:\n%v", len(lines), out.String())
+	}
+	if lines[0] != "name,zip,lat,long,district,representative,representative_bioguide_id,senators,senator_bioguide_ids,geocode_quality,error" {
+		t.Errorf("unexpected header %q", lines[0])
+	}
+	if want := "Bob,12345,,,NC-04,Rep  Price (D NC),P000523,Sen  Burr (R NC);Sen  Hagan (D NC),B001135;H001049,,"; lines[2] != want {
+		t.Errorf("row 2 = %q, want %q", lines[2], want)
+	}
+	if !strings.HasPrefix(lines[4], "Dave,") || !strings.HasSuffix(lines[4], ",no such zip") {
+		t.Errorf("row 4 missing error: %q", lines[4])
+	}
+	if !strings.HasPrefix(lines[5], "Eve,") || !strings.HasSuffix(lines[5], "coordinates or address\"") {
+		t.Errorf("row 5 missing error: %q", lines[5])
+	}
+}
+
+func TestBatchProcessorResume(t *testing.T) {
+	b, _ := testBatchProcessor()
+	var want bytes.Buffer
+	if _, err := b.Process(strings.NewReader(batchInput), &want); err != nil {
+		t.Fatal(err)
+	}
+
+	// A run interrupted after its checkpoint at the third row has written
+	// part of the fourth.
+	dir := t.TempDir()
+	b.ProgressFile = filepath.Join(dir, "progress")
+	path := filepath.Join(dir, "out.csv")
+	firstRows := strings.Join(strings.SplitAfter(batchInput, "\n")[:4], "")
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Process(strings.NewReader(firstRows), out); err != nil {
+		t.Fatal(err)
+	}
+	out.WriteString("Dave,99999,,,,,")
+	out.Close()
+
+	out, err = os.OpenFile(path, os.O_RDWR|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	stats, err := b.Process(strings.NewReader(batchInput), out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Skipped != 3 || stats.Rows != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if got, _ := os.ReadFile(path); string(got) != want.String() {
+		t.Errorf("resumed output:\n%s\nwant:\n%s", got, want.String())
+	}
+	if progress, _ := os.ReadFile(b.ProgressFile); string(progress) != fmt.Sprintf("5 %v\n", want.Len()) {
+		t.Errorf("progress = %q, want 5 rows and %v bytes", progress, want.Len())
+	}
+
+	// Resuming needs an output that can be truncated to the checkpoint.
+	if _, err := b.Process(strings.NewReader(batchInput), &bytes.Buffer{}); err == nil {
+		t.Error("resumed into a buffer without an error")
+	}
+}
+
+func TestBatchProcessorGeocodeQuality(t *testing.T) {
+	geocoder, err := NewCensusBatchGeocoder(strings.NewReader(censusBatchResults))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := testBatchProcessor()
+	b.Columns = BatchColumns{Address: "address"}
+	b.Geocoder = geocoder
+	input := "name,address\nAlice,\"100 Main St, Anytown, NC, 27513\"\nBob,\"5 Elm St, Salem\"\n"
+
+	var out bytes.Buffer
+	if _, err := b.Process(strings.NewReader(input), &out); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[1], ",B001135;H001049,non-exact,") || !strings.HasSuffix(lines[2], ",tie,Address could not be geocoded") {
+		t.Errorf("unexpected output:\n%v", out.String())
+	}
+}
+
+func TestBatchProcessorLookupCacheSize(t *testing.T) {
+	b, calls := testBatchProcessor()
+	b.Columns = BatchColumns{Zip: "zip"}
+	b.Concurrency = 1
+	b.LookupCacheSize = 1
+	stats, err := b.Process(strings.NewReader("zip\n11111\n22222\n11111\n11111\n"), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Lookups != 3 || *calls != 3 {
+		t.Errorf("got %v lookups and %v calls, want 3 with one cached result", stats.Lookups, *calls)
+	}
+}
+
+func TestBatchProcessorResumeEmpty(t *testing.T) {
+	b, _ := testBatchProcessor()
+	dir := t.TempDir()
+	b.ProgressFile = filepath.Join(dir, "progress")
+	path := filepath.Join(dir, "out.csv")
+	for i := 0; i < 2; i++ {
+		out, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := b.Process(strings.NewReader("name,zip,lat,long\n"), out); err != nil {
+			t.Fatal(err)
+		}
+		out.Close()
+	}
+	header := "name,zip,lat,long," + strings.Join(BatchOutputColumns, ",") + "\n"
+	if got, _ := os.ReadFile(path); string(got) != header {
+		t.Errorf("output after resuming:\n%s\nwant only the header", got)
+	}
+}
+
+// recordingGeocoder places every address at the same point, recording
+// the addresses it is sent.
+type recordingGeocoder struct {
+	lock      sync.Mutex
+	addresses []string
+}
+
+func (g *recordingGeocoder) Geocode(address string) (*GeocodeResult, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.addresses = append(g.addresses, address)
+	return &GeocodeResult{Address: address, Latitude: 35.778788, Longitude: -78.787805, Quality: GeocodeExact}, nil
+}
+
+func TestBatchProcessorGeocodesAddressAsWritten(t *testing.T) {
+	geocoder := &recordingGeocoder{}
+	b, _ := testBatchProcessor()
+	b.Columns = BatchColumns{Address: "address"}
+	b.Concurrency = 1
+	b.Geocoder = geocoder
+	input := "name,address\nAlice,\"100 Main St., Apt #2, Anytown\"\nBob,\"100 main st apt 2 anytown\"\n"
+	stats, err := b.Process(strings.NewReader(input), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Lookups != 1 || len(geocoder.addresses) != 1 || geocoder.addresses[0] != "100 Main St., Apt #2, Anytown" {
+		t.Errorf("got %v lookups geocoding %q, want one of the address as written", stats.Lookups, geocoder.addresses)
+	}
+}
@@ -0,0 +1,236 @@
+package gosunlight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var billAPIS struct {
+	get     sunlightAPI
+	getList sunlightAPI
+	search  sunlightAPI
+}
+
+func init() {
+	billAPIS.get = newSunlightAPI("bills", "get")
+	billAPIS.getList = newSunlightAPI("bills", "getList")
+	billAPIS.search = newSunlightAPI("bills", "search")
+}
+
+// Bill represents a bill or resolution from the sunlight api.
+type Bill struct {
+	BillID        string        `json:"bill_id"`
+	BillType      string        `json:"bill_type"`
+	Number        int           `json:"number"`
+	Congress      int           `json:"congress"`
+	Chamber       string        `json:"chamber"`
+	OfficialTitle string        `json:"official_title"`
+	ShortTitle    string        `json:"short_title"`
+	IntroducedOn  string        `json:"introduced_on"`
+	SponsorID     string        `json:"sponsor_id"`
+	CosponsorIDs  []string      `json:"cosponsor_ids"`
+	CommitteeIDs  []string      `json:"committee_ids"`
+	LastActionAt  string        `json:"last_action_at"`
+	History       BillHistory   `json:"history"`
+	Actions       []*BillAction `json:"actions"`
+}
+
+// BillAction is a single step in a bill's progress, such as a referral to
+// committee or a vote.
+type BillAction struct {
+	ActedAt string `json:"acted_at"`
+	Type    string `json:"type"`
+	Text    string `json:"text"`
+	Chamber string `json:"chamber"`
+	Result  string `json:"result"`
+	RollID  string `json:"roll_id"`
+}
+
+// BillHistory records the major milestones a bill has reached.
+type BillHistory struct {
+	Active              bool   `json:"active"`
+	HousePassageResult  string `json:"house_passage_result"`
+	SenatePassageResult string `json:"senate_passage_result"`
+	AwaitingSignature   bool   `json:"awaiting_signature"`
+	Vetoed              bool   `json:"vetoed"`
+	Enacted             bool   `json:"enacted"`
+}
+
+// String implements fmt.Stringer for bills
+func (b Bill) String() string {
+	title := b.ShortTitle
+	if title == "" {
+		title = b.OfficialTitle
+	}
+	return fmt.Sprintf("%v %v", b.BillID, title)
+}
+
+// Status summarizes how far a bill has progressed: "enacted", "vetoed",
+// "awaiting signature", "passed house", "passed senate", "failed",
+// "active" or "introduced".
+func (b Bill) Status() string {
+	h := b.History
+	switch {
+	case h.Enacted:
+		return "enacted"
+	case h.Vetoed:
+		return "vetoed"
+	case h.AwaitingSignature:
+		return "awaiting signature"
+	case h.HousePassageResult == "fail" || h.SenatePassageResult == "fail":
+		return "failed"
+	case h.HousePassageResult == "pass":
+		return "passed house"
+	case h.SenatePassageResult == "pass":
+		return "passed senate"
+	case h.Active:
+		return "active"
+	}
+	return "introduced"
+}
+
+// BillFilter limits the bills returned by BillGetList and BillSearch.
+// Fields left empty are not used.
+type BillFilter struct {
+	// SponsorID is the Bioguide ID of the bill's sponsor.
+	SponsorID string `param:"sponsor_id"`
+	// CommitteeID is the id of a committee the bill was referred to.
+	CommitteeID string `param:"committee_ids"`
+	// Congress is the number of the congress the bill was introduced in.
+	Congress int `param:"congress"`
+}
+
+// BillGet returns a single bill, including its actions, given a bill id
+// such as "hr3590-111".
+//
+// See: http://services.sunlightlabs.com/docs/congressapi/bills.get/
+func BillGet(billID string) (*Bill, error) {
+	var response billResponse
+	p := params{"bill_id": billID}
+	err := billAPIS.get.get(&response, p)
+	if err != nil {
+		return nil, err
+	}
+	if response.Response.Bill == nil {
+		return nil, fmt.Errorf("Bill %v not found", billID)
+	}
+	return response.Response.Bill, nil
+}
+
+// BillGetList returns the bills matching a filter.
+//
+// See: http://services.sunlightlabs.com/docs/congressapi/bills.getList/
+func BillGetList(filter BillFilter) ([]*Bill, error) {
+	return collectList(context.Background(), billAPIS.getList, "bills", (*billItem).bill, filter)
+}
+
+// BillSearch performs a full text search of bills for a keyword or phrase,
+// returning the bills that also match a filter.
+//
+// See: http://services.sunlightlabs.com/docs/congressapi/bills.search/
+func BillSearch(query string, filter BillFilter) ([]*Bill, error) {
+	p := params{"query": query}
+	return collectList(context.Background(), billAPIS.search, "bills", (*billItem).bill, p, filter)
+}
+
+// Sponsor returns the legislator who sponsored the bill.  The first call
+// will block while the legislator is fetched from sunlight.  Subsequent
+// calls return a cached value.
+func (b *Bill) Sponsor() (*Legislator, error) {
+	if b.SponsorID == "" {
+		return nil, errors.New("SponsorID missing for bill.")
+	}
+	return legislatorByID(b.SponsorID)
+}
+
+// Cosponsors returns the legislators who cosponsored the bill, in the
+// order of CosponsorIDs.  The first call will block while the legislators
+// are fetched from sunlight.  Subsequent calls return a cached list,
+// unless the bill has no BillID to cache it by.
+func (b *Bill) Cosponsors() ([]*Legislator, error) {
+	if len(b.CosponsorIDs) == 0 {
+		return []*Legislator{}, nil
+	}
+	if b.BillID == "" {
+		return legislatorsByID(b.CosponsorIDs)
+	}
+	return billCosponsors.get(b.BillID, func() ([]*Legislator, error) {
+		return legislatorsByID(b.CosponsorIDs)
+	})
+}
+
+// Invalidate discards the relations cached for this bill, so that they
+// are fetched again on next use.
+func (b *Bill) Invalidate() {
+	billCosponsors.forget(b.BillID)
+	billAmendments.forget(b.BillID)
+	billsByID.forget(b.BillID)
+}
+
+// legislatorByID returns a current or past legislator by Bioguide ID,
+// caching the result.  An unknown id is an error, and is not cached.
+func legislatorByID(bioguideID string) (*Legislator, error) {
+	return legislatorsByBioguideID.get(bioguideID, func() (*Legislator, error) {
+		l, err := LegislatorGetAll(Legislator{BioguideID: bioguideID})
+		if err != nil {
+			return nil, err
+		}
+		if l == nil {
+			return nil, fmt.Errorf("Legislator %v not found", bioguideID)
+		}
+		return l, nil
+	})
+}
+
+// maxIDsPerRequest is the number of ids legislatorsByID puts in a single
+// request, to keep its URLs well within server limits.
+const maxIDsPerRequest = 50
+
+// legislatorsByID fetches several current or past legislators, up to
+// maxIDsPerRequest in each request, returning them in the order of the
+// ids.
+func legislatorsByID(ids []string) ([]*Legislator, error) {
+	byID := make(map[string]*Legislator, len(ids))
+	for start := 0; start < len(ids); start += maxIDsPerRequest {
+		chunk := ids[start:min(start+maxIDsPerRequest, len(ids))]
+		filters := make([]*Legislator, 0, len(chunk))
+		for _, id := range chunk {
+			filters = append(filters, &Legislator{BioguideID: id})
+		}
+		found, err := LegislatorGetListAll(filters...)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range found {
+			byID[l.BioguideID] = l
+			legislatorsByBioguideID.set(l.BioguideID, l)
+		}
+	}
+	legislators := make([]*Legislator, 0, len(ids))
+	for _, id := range ids {
+		if l, ok := byID[id]; ok {
+			legislators = append(legislators, l)
+		}
+	}
+	return legislators, nil
+}
+
+type billResponse struct {
+	Response struct {
+		Bill *Bill
+	}
+}
+
+// billItem is an element of a list of bills.
+type billItem struct {
+	Bill *Bill
+}
+
+func (i *billItem) bill() *Bill {
+	return i.Bill
+}
+
+func (i *billItem) into(b *Bill) {
+	i.Bill = b
+}
@@ -0,0 +1,95 @@
+package gosunlight
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const billsJSON = `{"response": {"bills": [{"bill": {
+	"bill_id": "hr3590-111", "bill_type": "hr", "number": 3590, "congress": 111, "chamber": "house",
+	"short_title": "Patient Protection and Affordable Care Act", "sponsor_id": "R000053",
+	"cosponsor_ids": ["A000022", "B000490"], "committee_ids": ["HSWM"],
+	"history": {"active": true, "house_passage_result": "pass", "senate_passage_result": "pass", "enacted": true},
+	"actions": [{"acted_at": "2009-09-17", "type": "action", "text": "Referred to the House Committee on Ways and Means."}]
+}}]}}`
+
+func TestBillDecoding(t *testing.T) {
+	serve(t, func(req *Request) (string, error) { return billsJSON, nil })
+	bills, err := BillGetList(BillFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bills) != 1 {
+		t.Fatalf("got %v bills, want 1", len(bills))
+	}
+	b := bills[0]
+	if b.String() != "hr3590-111 Patient Protection and Affordable Care Act" || b.Number != 3590 || len(b.CosponsorIDs) != 2 {
+		t.Errorf("unexpected bill %+v", b)
+	}
+	if b.Status() != "enacted" || len(b.Actions) != 1 || b.Actions[0].ActedAt != "2009-09-17" {
+		t.Errorf("unexpected status %v or actions %v", b.Status(), b.Actions)
+	}
+}
+
+func TestBillFilter(t *testing.T) {
+	query := url.Values{}
+	BillFilter{SponsorID: "R000053", Congress: 111}.addTo(&query)
+	if query.Encode() != "congress=111&sponsor_id=R000053" {
+		t.Errorf("unexpected query %v", query.Encode())
+	}
+}
+
+func TestBillCosponsors(t *testing.T) {
+	var requests []int
+	serve(t, func(req *Request) (string, error) {
+		ids := req.Params["bioguide_id"]
+		requests = append(requests, len(ids))
+		var items []string
+		for _, id := range ids {
+			items = append(items, fmt.Sprintf(`{"legislator": {"bioguide_id": %q}}`, id))
+		}
+		return `{"response": {"legislators": [` + strings.Join(items, ",") + `]}}`, nil
+	})
+
+	var ids []string
+	for i := 0; i < 120; i++ {
+		ids = append(ids, fmt.Sprintf("C%06d", i))
+	}
+	b := &Bill{BillID: "hr1-113", CosponsorIDs: ids}
+	cosponsors, err := b.Cosponsors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cosponsors) != 120 || cosponsors[119].BioguideID != "C000119" {
+		t.Errorf("got %v cosponsors, want 120 in order", len(cosponsors))
+	}
+	if len(requests) != 3 || requests[0] != maxIDsPerRequest || requests[2] != 20 {
+		t.Errorf("requested %v ids at a time", requests)
+	}
+
+	// Bills without an id are not cached, so they cannot share cosponsors.
+	first, _ := (&Bill{CosponsorIDs: []string{"A000001"}}).Cosponsors()
+	second, _ := (&Bill{CosponsorIDs: []string{"B000002"}}).Cosponsors()
+	if len(first) != 1 || len(second) != 1 || second[0].BioguideID != "B000002" {
+		t.Errorf("bills without ids got cosponsors %v and %v", first, second)
+	}
+}
+
+func TestBillSponsorNotFound(t *testing.T) {
+	requests := 0
+	serve(t, func(req *Request) (string, error) {
+		requests++
+		return `{"response": {}}`, nil
+	})
+	b := &Bill{BillID: "hr1-113", SponsorID: "X000000"}
+	for i := 0; i < 2; i++ {
+		if sponsor, err := b.Sponsor(); err == nil || sponsor != nil {
+			t.Errorf("Sponsor() = %v, %v, want a not found error", sponsor, err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("made %v requests, want the miss not to be cached", requests)
+	}
+}
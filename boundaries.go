@@ -0,0 +1,248 @@
+package gosunlight
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+// OverlapResolution is the number of lines of latitude sampled when
+// measuring how much of one shape overlaps another.  Higher values are
+// more precise but slower.
+var OverlapResolution = 1000
+
+// Boundaries holds congressional district and county shapes for answering
+// questions about how they touch and overlap.  Shapes are usually loaded
+// from Census Bureau TIGER/Line or cartographic boundary files converted
+// to GeoJSON.
+type Boundaries struct {
+	districts map[string]*districtShape
+	counties  map[string]MultiPolygon
+
+	// edges indexes each border segment by the districts it borders.
+	edges map[edgeKey][]string
+}
+
+type districtShape struct {
+	district *District
+	shape    MultiPolygon
+	bounds   Bounds
+}
+
+type edgeKey struct {
+	a, b Point
+}
+
+// DistrictShare is the share of an area that falls in a district.
+type DistrictShare struct {
+	District *District
+	Share    float64
+}
+
+// NewBoundaries returns an empty set of boundaries.
+func NewBoundaries() *Boundaries {
+	return &Boundaries{
+		districts: make(map[string]*districtShape),
+		counties:  make(map[string]MultiPolygon),
+		edges:     make(map[edgeKey][]string),
+	}
+}
+
+// AddDistrict adds the shape of a district, replacing any shape already
+// loaded for it.
+func (b *Boundaries) AddDistrict(district *District, shape MultiPolygon) {
+	key := district.String()
+	if old, ok := b.districts[key]; ok {
+		old.shape.eachSegment(func(p, q Point) {
+			e := newEdgeKey(p, q)
+			b.edges[e] = removeString(b.edges[e], key)
+		})
+	}
+	b.districts[key] = &districtShape{district: district, shape: shape, bounds: shape.Bounds()}
+	shape.eachSegment(func(p, q Point) {
+		e := newEdgeKey(p, q)
+		b.edges[e] = append(b.edges[e], key)
+	})
+}
+
+// AddCounty adds the shape of a county, identified by its five digit FIPS
+// code.
+func (b *Boundaries) AddCounty(fips string, shape MultiPolygon) {
+	b.counties[fips] = shape
+}
+
+// LoadDistricts reads congressional district shapes from a GeoJSON feature
+// collection.  Each feature must have a Census GEOID property, such as
+// "3603" or "5600".
+func (b *Boundaries) LoadDistricts(r io.Reader) error {
+	features, err := readGeoJSON(r)
+	if err != nil {
+		return err
+	}
+	for _, f := range features {
+		district, err := DistrictForGEOID(f.property("GEOID", "GEOID20", "GEOID10"))
+		if err != nil {
+			return err
+		}
+		b.AddDistrict(district, f.Shape)
+	}
+	return nil
+}
+
+// LoadCounties reads county shapes from a GeoJSON feature collection.
+// Each feature must have a five digit Census GEOID property.
+func (b *Boundaries) LoadCounties(r io.Reader) error {
+	features, err := readGeoJSON(r)
+	if err != nil {
+		return err
+	}
+	for _, f := range features {
+		fips := f.property("GEOID", "GEOID20", "GEOID10")
+		if len(fips) != 5 {
+			return fmt.Errorf("Invalid county GEOID %q", fips)
+		}
+		b.AddCounty(fips, f.Shape)
+	}
+	return nil
+}
+
+// LoadDistrictsFile reads congressional district shapes from a GeoJSON
+// file on disk.
+func (b *Boundaries) LoadDistrictsFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return b.LoadDistricts(f)
+}
+
+// LoadCountiesFile reads county shapes from a GeoJSON file on disk.
+func (b *Boundaries) LoadCountiesFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return b.LoadCounties(f)
+}
+
+// Neighbors returns the districts that share a border with a district.
+// Districts that only meet at a corner are not neighbors.  Shared borders
+// must be made of the same points in both shapes, as they are in Census
+// boundary files.
+func (b *Boundaries) Neighbors(d *District) ([]*District, error) {
+	key := d.String()
+	shape, ok := b.districts[key]
+	if !ok {
+		return nil, fmt.Errorf("No boundary loaded for district %v", key)
+	}
+	seen := map[string]bool{key: true}
+	var neighbors []*District
+	shape.shape.eachSegment(func(p, q Point) {
+		for _, other := range b.edges[newEdgeKey(p, q)] {
+			if !seen[other] {
+				seen[other] = true
+				neighbors = append(neighbors, b.districts[other].district)
+			}
+		}
+	})
+	sortDistricts(neighbors)
+	return neighbors, nil
+}
+
+// DistrictsIntersecting returns the districts that overlap a polygon, such
+// as a city boundary.  Districts that only touch the edge of the polygon
+// are not included.
+func (b *Boundaries) DistrictsIntersecting(polygon Polygon) []*District {
+	shares := b.overlap(MultiPolygon{polygon})
+	districts := make([]*District, 0, len(shares))
+	for _, share := range shares {
+		districts = append(districts, share.District)
+	}
+	sortDistricts(districts)
+	return districts
+}
+
+// CountyDistrictOverlap returns the districts that a county spans, and the
+// share of the county's area in each.  Shares are sorted from largest to
+// smallest and sum to 1 when the loaded districts cover the county.
+func (b *Boundaries) CountyDistrictOverlap(countyFIPS string) ([]DistrictShare, error) {
+	county, ok := b.counties[countyFIPS]
+	if !ok {
+		return nil, fmt.Errorf("No boundary loaded for county %v", countyFIPS)
+	}
+	return b.overlap(county), nil
+}
+
+// overlap measures the share of a shape's area in each district.  The
+// shape is cut into OverlapResolution lines of latitude, and the overlap
+// is measured exactly along each line.
+func (b *Boundaries) overlap(shape MultiPolygon) []DistrictShare {
+	bounds := shape.Bounds()
+	var candidates []*districtShape
+	for _, d := range b.districts {
+		if d.bounds.Intersects(bounds) {
+			candidates = append(candidates, d)
+		}
+	}
+
+	rows := OverlapResolution
+	if rows <= 0 {
+		rows = 1
+	}
+	step := (bounds.Max.Latitude - bounds.Min.Latitude) / float64(rows)
+	total := 0.0
+	overlaps := make([]float64, len(candidates))
+	for i := 0; i < rows; i++ {
+		latitude := bounds.Min.Latitude + (float64(i)+.5)*step
+		// A degree of longitude shrinks with latitude.
+		scale := math.Cos(latitude * math.Pi / 180)
+		spans := shape.spans(latitude)
+		total += spanLength(spans) * scale
+		for j, d := range candidates {
+			if latitude >= d.bounds.Min.Latitude && latitude <= d.bounds.Max.Latitude {
+				overlaps[j] += spanOverlap(spans, d.shape.spans(latitude)) * scale
+			}
+		}
+	}
+
+	var shares []DistrictShare
+	for j, d := range candidates {
+		if total > 0 && overlaps[j] > 0 {
+			shares = append(shares, DistrictShare{District: d.district, Share: overlaps[j] / total})
+		}
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Share != shares[j].Share {
+			return shares[i].Share > shares[j].Share
+		}
+		return shares[i].District.String() < shares[j].District.String()
+	})
+	return shares
+}
+
+func newEdgeKey(p, q Point) edgeKey {
+	if q.Longitude < p.Longitude || (q.Longitude == p.Longitude && q.Latitude < p.Latitude) {
+		p, q = q, p
+	}
+	return edgeKey{p, q}
+}
+
+func removeString(list []string, s string) []string {
+	out := list[:0]
+	for _, item := range list {
+		if item != s {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func sortDistricts(districts []*District) {
+	sort.Slice(districts, func(i, j int) bool {
+		return districts[i].String() < districts[j].String()
+	})
+}
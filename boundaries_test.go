@@ -0,0 +1,81 @@
+package gosunlight
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// A two by two grid of square districts, numbered
+//
+//	3 4
+//	1 2
+const districtGrid = `{"type": "FeatureCollection", "features": [
+{"type": "Feature", "properties": {"GEOID": "3701"}, "geometry": {"type": "Polygon", "coordinates": [[[0,0],[1,0],[1,1],[0,1],[0,0]]]}},
+{"type": "Feature", "properties": {"GEOID": "3702"}, "geometry": {"type": "Polygon", "coordinates": [[[1,0],[2,0],[2,1],[1,1],[1,0]]]}},
+{"type": "Feature", "properties": {"GEOID": "3703"}, "geometry": {"type": "MultiPolygon", "coordinates": [[[[0,1],[1,1],[1,2],[0,2],[0,1]]]]}},
+{"type": "Feature", "properties": {"GEOID": "3704"}, "geometry": {"type": "Polygon", "coordinates": [[[1,1],[2,1],[2,2],[1,2],[1,1]]]}}
+]}`
+
+const countyGrid = `{"type": "FeatureCollection", "features": [
+{"type": "Feature", "properties": {"GEOID": "37001"}, "geometry": {"type": "Polygon", "coordinates": [[[0.5,0.5],[1.5,0.5],[1.5,1.5],[0.5,1.5],[0.5,0.5]]]}},
+{"type": "Feature", "properties": {"GEOID": "37003"}, "geometry": {"type": "Polygon", "coordinates": [[[0,0],[0.5,0],[0.5,2],[0,2],[0,0]]]}}
+]}`
+
+func testBoundaries(t *testing.T) *Boundaries {
+	b := NewBoundaries()
+	if err := b.LoadDistricts(strings.NewReader(districtGrid)); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.LoadCounties(strings.NewReader(countyGrid)); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestNeighbors(t *testing.T) {
+	b := testBoundaries(t)
+	neighbors, err := b.Neighbors(&District{State: "NC", Number: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(neighbors) != 2 || neighbors[0].String() != "NC-02" || neighbors[1].String() != "NC-03" {
+		t.Errorf("Neighbors(NC-01) = %v, want [NC-02 NC-03]", neighbors)
+	}
+	if _, err := b.Neighbors(&District{State: "NC", Number: "9"}); err == nil {
+		t.Error("expected error for unloaded district")
+	}
+}
+
+func TestDistrictsIntersecting(t *testing.T) {
+	b := testBoundaries(t)
+	polygon := Polygon{Ring{{0.2, 0.2}, {1.5, 0.2}, {1.5, 0.8}, {0.2, 0.8}}}
+	districts := b.DistrictsIntersecting(polygon)
+	if len(districts) != 2 || districts[0].String() != "NC-01" || districts[1].String() != "NC-02" {
+		t.Errorf("DistrictsIntersecting = %v, want [NC-01 NC-02]", districts)
+	}
+}
+
+func TestCountyDistrictOverlap(t *testing.T) {
+	b := testBoundaries(t)
+	shares, err := b.CountyDistrictOverlap("37001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 4 {
+		t.Fatalf("got %v shares, want 4", len(shares))
+	}
+	for _, share := range shares {
+		if math.Abs(share.Share-.25) > .001 {
+			t.Errorf("%v share = %v, want .25", share.District, share.Share)
+		}
+	}
+
+	shares, err = b.CountyDistrictOverlap("37003")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 2 || math.Abs(shares[0].Share-.5) > .001 {
+		t.Errorf("unexpected shares %v", shares)
+	}
+}
@@ -0,0 +1,213 @@
+package gosunlight
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// CacheExpiry is how long lazily loaded relations, such as a legislator's
+// committees or a district's representative, are cached before being
+// fetched again.  Zero, the default, caches them until they are
+// invalidated.
+var CacheExpiry time.Duration
+
+// CacheSize is the number of values kept in each cache of lazily loaded
+// relations, such as the committees of CacheSize legislators.  When a
+// cache is full, the least recently used value is evicted.  Zero or less
+// keeps every value.
+var CacheSize = 1000
+
+// The caches behind lazily loaded relations.  They are keyed by the
+// identity of the entity, not its address, so every copy of a legislator
+// or district shares the same cached values.
+var (
+	legislatorCommittees    cache[string, []*Committee]
+	districtRepresentative  cache[string, *Legislator]
+	stateSenators           cache[string, []*Legislator]
+	legislatorsByBioguideID cache[string, *Legislator]
+	billCosponsors          cache[string, []*Legislator]
+	legislatorVotes         cache[VoteFilter, []*MemberVote]
+	legislatorAmendments    cache[string, []*Amendment]
+	billsByID               cache[string, *Bill]
+	billAmendments          cache[string, []*Amendment]
+	committeeRosters        cache[string, *Committee]
+)
+
+// ClearCache discards every cached relation.
+func ClearCache() {
+	legislatorCommittees.clear()
+	districtRepresentative.clear()
+	stateSenators.clear()
+	legislatorsByBioguideID.clear()
+	billCosponsors.clear()
+	legislatorVotes.clear()
+	legislatorAmendments.clear()
+	billsByID.clear()
+	billAmendments.clear()
+	committeeRosters.clear()
+}
+
+// cache is a concurrency safe map of lazily loaded values.  Concurrent
+// requests for the same key share a single load, successful loads are
+// kept until they expire, are evicted or are forgotten, and failed loads
+// are retried on the next request.  The zero value is an empty cache.
+type cache[K comparable, V any] struct {
+	lock    sync.Mutex
+	entries map[K]*cacheEntry[V]
+
+	// recent orders the entries' keys from most to least recently used.
+	recent list.List
+
+	// size, if set, is used in place of CacheSize.
+	size int
+}
+
+type cacheEntry[V any] struct {
+	done   chan struct{}
+	value  V
+	err    error
+	loaded time.Time
+	used   *list.Element
+}
+
+// get returns the cached value for key, calling load if there is none.
+func (c *cache[K, V]) get(key K, load func() (V, error)) (V, error) {
+	c.lock.Lock()
+	if entry, ok := c.entries[key]; ok {
+		select {
+		case <-entry.done:
+			if entry.err == nil && !expired(entry.loaded) {
+				c.recent.MoveToFront(entry.used)
+				c.lock.Unlock()
+				return entry.value, nil
+			}
+		default:
+			c.lock.Unlock()
+			<-entry.done
+			return entry.value, entry.err
+		}
+	}
+	entry := &cacheEntry[V]{done: make(chan struct{})}
+	c.add(key, entry)
+	c.lock.Unlock()
+
+	entry.value, entry.err = load()
+	entry.loaded = time.Now()
+	close(entry.done)
+	if entry.err != nil {
+		c.lock.Lock()
+		if c.entries[key] == entry {
+			c.remove(key)
+		}
+		c.lock.Unlock()
+	}
+	return entry.value, entry.err
+}
+
+// set caches a value that was loaded elsewhere.  Only values as complete
+// as those loaded by get may be set, as they are returned in their place.
+func (c *cache[K, V]) set(key K, value V) {
+	entry := &cacheEntry[V]{done: make(chan struct{}), value: value, loaded: time.Now()}
+	close(entry.done)
+	c.lock.Lock()
+	c.add(key, entry)
+	c.lock.Unlock()
+}
+
+// update caches the result of fn, which is passed the cached value and
+// whether there is one, so that values loaded elsewhere can be merged
+// into those already cached.  A load in progress is not waited for, and
+// is treated as no value.
+func (c *cache[K, V]) update(key K, fn func(value V, ok bool) V) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var value V
+	ok := false
+	if entry, found := c.entries[key]; found {
+		select {
+		case <-entry.done:
+			if entry.err == nil && !expired(entry.loaded) {
+				value, ok = entry.value, true
+			}
+		default:
+		}
+	}
+	entry := &cacheEntry[V]{done: make(chan struct{}), value: fn(value, ok), loaded: time.Now()}
+	close(entry.done)
+	c.add(key, entry)
+}
+
+// add stores an entry as the most recently used, replacing any entry for
+// the same key, then evicts expired and least recently used entries until
+// the cache is within its size.  The lock must be held.
+func (c *cache[K, V]) add(key K, entry *cacheEntry[V]) {
+	if c.entries == nil {
+		c.entries = make(map[K]*cacheEntry[V])
+	}
+	c.remove(key)
+	entry.used = c.recent.PushFront(key)
+	c.entries[key] = entry
+
+	for back := c.recent.Back(); back != nil && back != entry.used; back = c.recent.Back() {
+		oldest := c.entries[back.Value.(K)]
+		size := c.size
+		if size == 0 {
+			size = CacheSize
+		}
+		full := size > 0 && len(c.entries) > size
+		if !full && !oldest.stale() {
+			break
+		}
+		c.remove(back.Value.(K))
+	}
+}
+
+// remove discards the entry for key, if any.  The lock must be held.
+func (c *cache[K, V]) remove(key K) {
+	if entry, ok := c.entries[key]; ok {
+		c.recent.Remove(entry.used)
+		delete(c.entries, key)
+	}
+}
+
+// forget discards the cached value for key.  A load already in progress
+// still completes for the callers waiting on it.
+func (c *cache[K, V]) forget(key K) {
+	c.lock.Lock()
+	c.remove(key)
+	c.lock.Unlock()
+}
+
+// forgetWhere discards the cached values for every key matching fn.
+func (c *cache[K, V]) forgetWhere(fn func(K) bool) {
+	c.lock.Lock()
+	for key := range c.entries {
+		if fn(key) {
+			c.remove(key)
+		}
+	}
+	c.lock.Unlock()
+}
+
+// clear discards every cached value.
+func (c *cache[K, V]) clear() {
+	c.lock.Lock()
+	c.entries = nil
+	c.recent.Init()
+	c.lock.Unlock()
+}
+
+// stale reports whether an entry has finished loading and expired.
+func (e *cacheEntry[V]) stale() bool {
+	select {
+	case <-e.done:
+		return expired(e.loaded)
+	default:
+		return false
+	}
+}
+
+func expired(loaded time.Time) bool {
+	return CacheExpiry > 0 && time.Since(loaded) > CacheExpiry
+}
@@ -0,0 +1,94 @@
+package gosunlight
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheSharesLoads(t *testing.T) {
+	var c cache[string, int]
+	var loads int32
+	release := make(chan struct{})
+	load := func() (int, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := c.get("key", load); v != 42 || err != nil {
+				t.Errorf("get = %v, %v", v, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if v, _ := c.get("key", load); v != 42 || loads != 1 {
+		t.Errorf("got %v after %v loads, want 42 after 1", v, loads)
+	}
+}
+
+func TestCacheRetriesErrors(t *testing.T) {
+	var c cache[string, int]
+	if _, err := c.get("key", func() (int, error) { return 0, errors.New("failed") }); err == nil {
+		t.Error("expected error")
+	}
+	if v, err := c.get("key", func() (int, error) { return 1, nil }); v != 1 || err != nil {
+		t.Errorf("get after error = %v, %v", v, err)
+	}
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	var c cache[string, int]
+	n := 0
+	load := func() (int, error) { n++; return n, nil }
+
+	c.get("key", load)
+	c.forget("key")
+	if v, _ := c.get("key", load); v != 2 {
+		t.Errorf("get after forget = %v, want 2", v)
+	}
+
+	defer func(expiry time.Duration) { CacheExpiry = expiry }(CacheExpiry)
+	CacheExpiry = time.Millisecond
+	time.Sleep(2 * time.Millisecond)
+	if v, _ := c.get("key", load); v != 3 {
+		t.Errorf("get after expiry = %v, want 3", v)
+	}
+
+	c.set("key", 10)
+	CacheExpiry = 0
+	if v, _ := c.get("key", load); v != 10 {
+		t.Errorf("get after set = %v, want 10", v)
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	defer func(size int, expiry time.Duration) { CacheSize, CacheExpiry = size, expiry }(CacheSize, CacheExpiry)
+	CacheSize = 2
+	var c cache[string, int]
+	c.set("a", 1)
+	c.set("b", 2)
+	c.get("a", nil)
+	c.set("c", 3)
+	if _, ok := c.entries["b"]; ok || len(c.entries) != 2 || c.recent.Len() != 2 {
+		t.Errorf("least recently used entry not evicted: %v", c.entries)
+	}
+
+	CacheSize = 0
+	CacheExpiry = time.Millisecond
+	time.Sleep(2 * time.Millisecond)
+	c.set("d", 4)
+	if len(c.entries) != 1 || c.recent.Len() != 1 {
+		t.Errorf("expired entries not evicted: %v", c.entries)
+	}
+}
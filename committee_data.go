@@ -0,0 +1,140 @@
+package gosunlight
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// CommitteeKind is the type of a committee.
+type CommitteeKind string
+
+const (
+	CommitteeStanding CommitteeKind = "standing"
+	CommitteeSelect   CommitteeKind = "select"
+	CommitteeJoint    CommitteeKind = "joint"
+	CommitteeSpecial  CommitteeKind = "special"
+)
+
+// committeeData holds the committee details that sunlight does not
+// provide, keyed by THOMAS id.
+var committeeData struct {
+	sync.RWMutex
+	records map[string]committeeRecord
+}
+
+type committeeRecord struct {
+	Type         string `json:"type"`
+	Name         string `json:"name"`
+	URL          string `json:"url"`
+	Jurisdiction string `json:"jurisdiction"`
+	Address      string `json:"address"`
+	Phone        string `json:"phone"`
+}
+
+// LoadCommitteeData reads committee details from a file in the format of
+// the unitedstates project's committees-current.json.  Once loaded, the
+// details are added to every committee returned by the committee
+// functions.  Loading a second file adds to, and overrides, the first.
+//
+// See: https://github.com/unitedstates/congress-legislators
+func LoadCommitteeData(r io.Reader) error {
+	var committees []struct {
+		committeeRecord
+		ThomasID      string `json:"thomas_id"`
+		Subcommittees []struct {
+			committeeRecord
+			ThomasID string `json:"thomas_id"`
+		} `json:"subcommittees"`
+	}
+	if err := json.NewDecoder(r).Decode(&committees); err != nil {
+		return err
+	}
+
+	committeeData.Lock()
+	defer committeeData.Unlock()
+	if committeeData.records == nil {
+		committeeData.records = make(map[string]committeeRecord)
+	}
+	for _, c := range committees {
+		committeeData.records[c.ThomasID] = c.committeeRecord
+		for _, sc := range c.Subcommittees {
+			committeeData.records[c.ThomasID+sc.ThomasID] = sc.committeeRecord
+		}
+	}
+	return nil
+}
+
+// LoadCommitteeDataFile reads committee details from a file on disk.  See
+// LoadCommitteeData.
+func LoadCommitteeDataFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return LoadCommitteeData(f)
+}
+
+// CongressGovCode returns the code congress.gov uses for the committee,
+// such as "hsag00" for a full committee or "hsag15" for a subcommittee.
+func (c *Committee) CongressGovCode() string {
+	code := strings.ToLower(c.ThomasID)
+	if len(code) == 4 {
+		code += "00"
+	}
+	return code
+}
+
+// ThomasIDForCongressGovCode returns the THOMAS id, which is also the
+// sunlight committee id, for a congress.gov committee code.
+func ThomasIDForCongressGovCode(code string) string {
+	code = strings.ToUpper(code)
+	if len(code) == 6 && strings.HasSuffix(code, "00") {
+		code = code[:4]
+	}
+	return code
+}
+
+// addMetadata fills in the committee's kind and any details loaded with
+// LoadCommitteeData, for the committee and each of its subcommittees.
+func (c *Committee) addMetadata() {
+	committeeData.RLock()
+	defer committeeData.RUnlock()
+	c.Walk(func(c *Committee) error {
+		if c.ThomasID == "" {
+			c.ThomasID = c.Id
+		}
+		record, ok := committeeData.records[c.ThomasID]
+		c.Kind = committeeKind(c, record)
+		if ok {
+			c.Jurisdiction = record.Jurisdiction
+			c.Website = record.URL
+			c.Phone = record.Phone
+			c.Office = record.Address
+		}
+		return nil
+	})
+}
+
+// committeeKind works out a committee's kind from its name, chamber and
+// any loaded record.  The record's type only names the chamber, or
+// "joint", so select and special committees are found by name.
+// Subcommittees share the kind of their parent.
+func committeeKind(c *Committee, record committeeRecord) CommitteeKind {
+	if c.Parent != nil && c.Parent.Kind != "" {
+		return c.Parent.Kind
+	}
+	name := strings.ToLower(c.Name + " " + record.Name)
+	switch {
+	case strings.Contains(name, "select"):
+		return CommitteeSelect
+	case strings.Contains(name, "special"):
+		return CommitteeSpecial
+	case record.Type == "joint" || c.Chamber == "Joint" || strings.HasPrefix(c.ThomasID, "J"):
+		return CommitteeJoint
+	}
+	return CommitteeStanding
+}
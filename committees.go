@@ -1,8 +1,10 @@
 package gosunlight
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var committeeAPIS struct {
@@ -23,9 +25,87 @@ type Committee struct {
 	Id            string `json:"id"`
 	Name          string `json:"name"`
 	Members       []*Legislator
+	Memberships   []*Membership
 	Subcommittees []*Committee
+
+	// Jurisdiction, Website, Phone and Office are only set once committee
+	// details are loaded with LoadCommitteeData.
+	Kind         CommitteeKind `json:"kind"`
+	Jurisdiction string        `json:"jurisdiction"`
+	Website      string        `json:"website"`
+	Phone        string        `json:"phone"`
+	Office       string        `json:"office"`
+
+	// ThomasID is the committee's code in THOMAS, such as "HSAG" for the
+	// House Committee on Agriculture or "HSAG15" for one of its
+	// subcommittees.  Sunlight committee ids are THOMAS ids.
+	ThomasID string `json:"thomas_id"`
+
+	// Parent is the committee this is a subcommittee of, or nil for a
+	// full committee.
+	Parent *Committee `json:"-"`
 }
 
+// MemberRole is the position a legislator holds on a committee.
+type MemberRole string
+
+const (
+	RoleMember        MemberRole = "Member"
+	RoleChair         MemberRole = "Chair"
+	RoleViceChair     MemberRole = "Vice Chair"
+	RoleRankingMember MemberRole = "Ranking Member"
+	RoleExOfficio     MemberRole = "Ex Officio"
+)
+
+// parseMemberRole translates the titles used by sunlight, such as
+// "Chairman" or "Ranking Minority Member", into a MemberRole.
+func parseMemberRole(title string) MemberRole {
+	title = strings.ToLower(strings.TrimSpace(title))
+	switch {
+	case title == "":
+		return RoleMember
+	case strings.HasPrefix(title, "vice chair"):
+		return RoleViceChair
+	case strings.HasPrefix(title, "chair"):
+		return RoleChair
+	case strings.HasPrefix(title, "ranking"):
+		return RoleRankingMember
+	case strings.HasPrefix(title, "ex officio"):
+		return RoleExOfficio
+	}
+	return RoleMember
+}
+
+// Membership is a legislator's seat on a committee.
+type Membership struct {
+	Committee  *Committee
+	Legislator *Legislator
+	Role       MemberRole
+
+	// Rank is the legislator's seniority on their side of the committee,
+	// starting at 1.
+	Rank int
+
+	// Side is "majority" or "minority".
+	Side string
+
+	StartDate string
+}
+
+// String implements fmt.Stringer for memberships
+func (m Membership) String() string {
+	return fmt.Sprintf("%v, %v of %v", m.Legislator, m.Role, m.Committee.Name)
+}
+
+// Leadership reports whether the membership is a chair, vice chair, or
+// ranking member.
+func (m Membership) Leadership() bool {
+	return m.Role == RoleChair || m.Role == RoleViceChair || m.Role == RoleRankingMember
+}
+
+// Chambers lists the chambers that committees belong to.
+var Chambers = []string{"House", "Senate", "Joint"}
+
 //Implements fmt.Stringer for committees
 func (committee Committee) String() string {
 	return fmt.Sprintf("%7v %v %v", committee.Chamber, committee.Id, committee.Name)
@@ -50,9 +130,13 @@ func CommitteeGetList(chamber string) ([]*Committee, error) {
 //
 // See: http://services.sunlightlabs.com/docs/congressapi/committees.get/
 func CommitteeGet(id string) (*Committee, error) {
+	return committeeGet(context.Background(), id)
+}
+
+func committeeGet(ctx context.Context, id string) (*Committee, error) {
 	var response committeeResponse
 	p := params{"id": id}
-	err := committeeAPIS.get.get(&response, p)
+	err := committeeAPIS.get.getContext(ctx, &response, p)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +158,7 @@ func CommitteesForLegislator(bioguideID string) ([]*Committee, error) {
 }
 
 // GetMembers is a convenience wrapper for CommitteeGet which populates
-// the Members field of a Committee.
+// the Members and Memberships fields of a Committee.
 func (c *Committee) GetMembers() error {
 	if c.Id == "" {
 		return errors.New("Cannot get members of committee: missing id")
@@ -84,9 +168,144 @@ func (c *Committee) GetMembers() error {
 		return err
 	}
 	c.Members = committee.Members
+	c.Memberships = committee.Memberships
+	for _, m := range c.Memberships {
+		m.Committee = c
+	}
+	return nil
+}
+
+// CommitteeTree returns the full committees of a chamber, with their
+// subcommittees linked to them as children.  If chamber is empty, the
+// committees for the House, Senate and Joint committees are all returned.
+func CommitteeTree(chamber string) ([]*Committee, error) {
+	chambers := []string{chamber}
+	if chamber == "" {
+		chambers = Chambers
+	}
+	var tree []*Committee
+	for _, c := range chambers {
+		committees, err := CommitteeGetList(c)
+		if err != nil {
+			return nil, err
+		}
+		tree = append(tree, committees...)
+	}
+	return tree, nil
+}
+
+// Walk calls fn for the committee and each of its subcommittees, parents
+// before children.  If fn returns an error, the walk stops and that error
+// is returned.
+func (c *Committee) Walk(fn func(*Committee) error) error {
+	if err := fn(c); err != nil {
+		return err
+	}
+	return WalkCommittees(c.Subcommittees, fn)
+}
+
+// Find returns the committee or subcommittee with an id, or nil if the id
+// is not in this committee's tree.
+func (c *Committee) Find(id string) *Committee {
+	return FindCommittee([]*Committee{c}, id)
+}
+
+// Flatten returns the committee and all of its subcommittees, parents
+// before children.
+func (c *Committee) Flatten() []*Committee {
+	return FlattenCommittees([]*Committee{c})
+}
+
+// Path returns the chain of committees from the full committee down to
+// this one.
+func (c *Committee) Path() []*Committee {
+	var path []*Committee
+	for committee := c; committee != nil; committee = committee.Parent {
+		path = append([]*Committee{committee}, path...)
+	}
+	return path
+}
+
+// WalkCommittees calls Walk on each committee in a list, stopping at the
+// first error.
+func WalkCommittees(committees []*Committee, fn func(*Committee) error) error {
+	for _, c := range committees {
+		if err := c.Walk(fn); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// errFound stops a walk once a committee has been found.
+var errFound = errors.New("found")
+
+// FindCommittee returns the committee or subcommittee with an id from a
+// list of committee trees, or nil if none match.
+func FindCommittee(committees []*Committee, id string) *Committee {
+	var found *Committee
+	WalkCommittees(committees, func(c *Committee) error {
+		if c.Id == id {
+			found = c
+			return errFound
+		}
+		return nil
+	})
+	return found
+}
+
+// FlattenCommittees returns every committee and subcommittee in a list of
+// committee trees, parents before children.
+func FlattenCommittees(committees []*Committee) []*Committee {
+	var flat []*Committee
+	WalkCommittees(committees, func(c *Committee) error {
+		flat = append(flat, c)
+		return nil
+	})
+	return flat
+}
+
+// Chair returns the chair's membership, or nil if the committee's members
+// have not been loaded or it has no chair.
+func (c *Committee) Chair() *Membership {
+	return c.membershipWithRole(RoleChair)
+}
+
+// RankingMember returns the ranking member's membership, or nil if the
+// committee's members have not been loaded or it has no ranking member.
+func (c *Committee) RankingMember() *Membership {
+	return c.membershipWithRole(RoleRankingMember)
+}
+
+func (c *Committee) membershipWithRole(role MemberRole) *Membership {
+	for _, m := range c.Memberships {
+		if m.Role == role {
+			return m
+		}
+	}
+	return nil
+}
+
+// Chairs returns the chair of each full committee in a chamber.  This
+// fetches every committee from sunlight.
+func Chairs(chamber string) ([]*Membership, error) {
+	committees, err := CommitteeGetList(chamber)
+	if err != nil {
+		return nil, err
+	}
+	opts := &RosterOptions{SkipSubcommittees: true}
+	if err := LoadRosters(context.Background(), committees, opts); err != nil {
+		return nil, err
+	}
+	var chairs []*Membership
+	for _, c := range committees {
+		if chair := c.Chair(); chair != nil {
+			chairs = append(chairs, chair)
+		}
+	}
+	return chairs, nil
+}
+
 type committeeResponse struct {
 	Response struct {
 		Committee struct {
@@ -98,6 +317,10 @@ type committeeResponse struct {
 			}
 			Members []struct {
 				Legislator *Legislator
+				Title      string
+				Rank       int
+				Side       string
+				StartDate  string `json:"start_date"`
 			}
 		}
 	}
@@ -110,44 +333,71 @@ func (cr committeeResponse) committee() *Committee {
 		Chamber:       cr.Response.Committee.Chamber,
 		Subcommittees: make([]*Committee, 0, len(cr.Response.Committee.Subcommittees)),
 		Members:       make([]*Legislator, 0, len(cr.Response.Committee.Members)),
+		Memberships:   make([]*Membership, 0, len(cr.Response.Committee.Members)),
 	}
 	for _, m := range cr.Response.Committee.Members {
 		c.Members = append(c.Members, m.Legislator)
+		c.Memberships = append(c.Memberships, &Membership{
+			Committee:  &c,
+			Legislator: m.Legislator,
+			Role:       parseMemberRole(m.Title),
+			Rank:       m.Rank,
+			Side:       m.Side,
+			StartDate:  m.StartDate,
+		})
 	}
 	for _, sc := range cr.Response.Committee.Subcommittees {
+		sc.Committee.Parent = &c
 		c.Subcommittees = append(c.Subcommittees, sc.Committee)
 	}
+	c.addMetadata()
 	return &c
 }
 
 type committeesResponse struct {
 	Response struct {
-		Committees []struct {
-			Committee struct {
-				Id            string
-				Name          string
-				Chamber       string
-				Subcommittees []struct {
-					Committee *Committee
-				}
-			}
-		}
+		Committees []committeeItem
+	}
+}
+
+// committeeItem is an element of a list of committees.
+type committeeItem struct {
+	Committee listedCommittee
+}
+
+func (i *committeeItem) committee() *Committee {
+	return i.Committee.committee()
+}
+
+// listedCommittee is a committee as it appears in committee lists.
+type listedCommittee struct {
+	Id            string
+	Name          string
+	Chamber       string
+	Subcommittees []struct {
+		Committee *Committee
+	}
+}
+
+func (c *listedCommittee) committee() *Committee {
+	committee := &Committee{
+		Name:          c.Name,
+		Id:            c.Id,
+		Chamber:       c.Chamber,
+		Subcommittees: make([]*Committee, 0, len(c.Subcommittees)),
+	}
+	for _, sc := range c.Subcommittees {
+		sc.Committee.Parent = committee
+		committee.Subcommittees = append(committee.Subcommittees, sc.Committee)
 	}
+	committee.addMetadata()
+	return committee
 }
 
 func (lc committeesResponse) committees() []*Committee {
 	committees := make([]*Committee, 0, len(lc.Response.Committees))
 	for _, c := range lc.Response.Committees {
-		committee := &Committee{
-			Name:          c.Committee.Name,
-			Id:            c.Committee.Id,
-			Chamber:       c.Committee.Chamber,
-			Subcommittees: make([]*Committee, 0, len(c.Committee.Subcommittees)),
-		}
-		for _, sc := range c.Committee.Subcommittees {
-			committee.Subcommittees = append(committee.Subcommittees, sc.Committee)
-		}
-		committees = append(committees, committee)
+		committees = append(committees, c.Committee.committee())
 	}
 	return committees
 }
@@ -0,0 +1,136 @@
+package gosunlight
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const committeesJSON = `{"response": {"committees": [
+	{"committee": {"id": "HSAG", "name": "Agriculture", "chamber": "House", "subcommittees": [
+		{"committee": {"id": "HSAG03", "name": "Conservation", "chamber": "House"}},
+		{"committee": {"id": "HSAG15", "name": "Livestock", "chamber": "House"}}
+	]}},
+	{"committee": {"id": "HSAP", "name": "Appropriations", "chamber": "House", "subcommittees": []}}
+]}}`
+
+func TestCommitteeTree(t *testing.T) {
+	var response committeesResponse
+	if err := json.Unmarshal([]byte(committeesJSON), &response); err != nil {
+		t.Fatal(err)
+	}
+	tree := response.committees()
+
+	livestock := FindCommittee(tree, "HSAG15")
+	if livestock == nil {
+		t.Fatal("FindCommittee(HSAG15) = nil")
+	}
+	if livestock.Parent == nil || livestock.Parent.Id != "HSAG" {
+		t.Errorf("HSAG15 parent = %v, want HSAG", livestock.Parent)
+	}
+	path := livestock.Path()
+	if len(path) != 2 || path[0].Id != "HSAG" || path[1].Id != "HSAG15" {
+		t.Errorf("Path() = %v", path)
+	}
+	if tree[0].Find("HSAP") != nil {
+		t.Error("HSAG.Find(HSAP) should be nil")
+	}
+
+	var ids []string
+	for _, c := range FlattenCommittees(tree) {
+		ids = append(ids, c.Id)
+	}
+	if len(ids) != 4 || ids[0] != "HSAG" || ids[1] != "HSAG03" || ids[2] != "HSAG15" || ids[3] != "HSAP" {
+		t.Errorf("FlattenCommittees = %v", ids)
+	}
+}
+
+const committeeJSON = `{"response": {"committee": {"id": "HSAG", "name": "Agriculture", "chamber": "House", "members": [
+	{"legislator": {"bioguide_id": "L000491", "lastname": "Lucas"}, "title": "Chairman", "rank": 1, "side": "majority"},
+	{"legislator": {"bioguide_id": "P000258", "lastname": "Peterson"}, "title": "Ranking Member", "rank": 1, "side": "minority"},
+	{"legislator": {"bioguide_id": "G000289", "lastname": "Goodlatte"}, "rank": 2, "side": "majority", "start_date": "2011-01-05"}
+]}}}`
+
+func TestCommitteeMemberships(t *testing.T) {
+	var response committeeResponse
+	if err := json.Unmarshal([]byte(committeeJSON), &response); err != nil {
+		t.Fatal(err)
+	}
+	committee := response.committee()
+	if len(committee.Members) != 3 || len(committee.Memberships) != 3 {
+		t.Fatalf("got %v members and %v memberships, want 3", len(committee.Members), len(committee.Memberships))
+	}
+	if chair := committee.Chair(); chair == nil || chair.Legislator.LastName != "Lucas" || chair.Side != "majority" {
+		t.Errorf("Chair() = %v", chair)
+	}
+	if ranking := committee.RankingMember(); ranking == nil || ranking.Legislator.LastName != "Peterson" {
+		t.Errorf("RankingMember() = %v", ranking)
+	}
+	member := committee.Memberships[2]
+	if member.Role != RoleMember || member.Rank != 2 || member.StartDate != "2011-01-05" || member.Leadership() {
+		t.Errorf("unexpected membership %+v", member)
+	}
+	if member.Committee != committee {
+		t.Error("membership not linked to its committee")
+	}
+}
+
+const committeeDataJSON = `[
+	{"type": "house", "name": "House Committee on Agriculture", "url": "http://agriculture.house.gov/",
+	 "thomas_id": "HSAG", "jurisdiction": "Agriculture generally.", "address": "1301 LHOB; Washington 20515", "phone": "(202) 225-2171",
+	 "subcommittees": [{"name": "Livestock", "thomas_id": "15", "phone": "(202) 225-2990"}]},
+	{"type": "house", "name": "House Permanent Select Committee on Intelligence", "thomas_id": "HLIG"},
+	{"type": "senate", "name": "Senate Special Committee on Aging", "thomas_id": "SPAG"},
+	{"type": "senate", "name": "Senate Committee on Finance", "thomas_id": "SSFI"},
+	{"type": "joint", "name": "Joint Economic Committee", "thomas_id": "JSEC"}
+]`
+
+func TestCommitteeData(t *testing.T) {
+	defer func() { committeeData.records = nil }()
+	if err := LoadCommitteeData(strings.NewReader(committeeDataJSON)); err != nil {
+		t.Fatal(err)
+	}
+	var response committeesResponse
+	if err := json.Unmarshal([]byte(committeesJSON), &response); err != nil {
+		t.Fatal(err)
+	}
+	tree := response.committees()
+
+	ag := tree[0]
+	if ag.Jurisdiction != "Agriculture generally." || ag.Website != "http://agriculture.house.gov/" || ag.Office != "1301 LHOB; Washington 20515" {
+		t.Errorf("metadata not applied: %+v", ag)
+	}
+	if ag.Kind != CommitteeStanding || ag.CongressGovCode() != "hsag00" {
+		t.Errorf("Kind = %v, CongressGovCode = %v", ag.Kind, ag.CongressGovCode())
+	}
+	livestock := ag.Find("HSAG15")
+	if livestock.Phone != "(202) 225-2990" || livestock.CongressGovCode() != "hsag15" {
+		t.Errorf("subcommittee metadata not applied: %+v", livestock)
+	}
+	if ThomasIDForCongressGovCode("hsag00") != "HSAG" || ThomasIDForCongressGovCode("hsag15") != "HSAG15" {
+		t.Error("ThomasIDForCongressGovCode failed")
+	}
+	if livestock.Kind != CommitteeStanding {
+		t.Errorf("subcommittee Kind = %v, want the kind of its parent", livestock.Kind)
+	}
+
+	// Sunlight's names leave out "Select" and "Special", which are read
+	// from the loaded names instead.
+	kinds := []struct {
+		committee Committee
+		kind      CommitteeKind
+	}{
+		{Committee{Id: "HLIG", Name: "Intelligence", Chamber: "House"}, CommitteeSelect},
+		{Committee{Id: "SPAG", Name: "Aging", Chamber: "Senate"}, CommitteeSpecial},
+		{Committee{Id: "SSFI", Name: "Finance", Chamber: "Senate"}, CommitteeStanding},
+		{Committee{Id: "JSEC", Name: "Economic", Chamber: "Senate"}, CommitteeJoint},
+		{Committee{Id: "HSSO", Name: "Select Committee on Benghazi", Chamber: "House"}, CommitteeSelect},
+	}
+	for _, test := range kinds {
+		c := test.committee
+		c.addMetadata()
+		if c.Kind != test.kind {
+			t.Errorf("%v Kind = %v, want %v", c.Id, c.Kind, test.kind)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package gosunlight
+
+import (
+	"errors"
+	"sync"
+)
+
+// Delegation is the complete congressional delegation for a single
+// district: its member of the House and the senators for its state.
+type Delegation struct {
+	State          string
+	District       *District
+	Representative *Legislator
+	Senators       []*Legislator
+}
+
+// AtLarge reports whether the delegation's district is an at-large seat
+// covering an entire state.
+func (d *Delegation) AtLarge() bool {
+	return d.District != nil && d.District.Kind() == DistrictAtLarge
+}
+
+// NonVoting reports whether the delegation is for DC or a territory.  These
+// delegations have a non-voting House member and no senators.
+func (d *Delegation) NonVoting() bool {
+	return d.District != nil && !d.District.Kind().Voting()
+}
+
+// DelegationForDistrict returns the delegation for a district.  The
+// representative and senators are fetched from sunlight concurrently.
+func DelegationForDistrict(district *District) (*Delegation, error) {
+	if district == nil || district.State == "" || district.Number == "" {
+		return nil, errors.New("State or number missing from district; cannot get delegation")
+	}
+	delegation := &Delegation{State: district.State, District: district}
+
+	var wg sync.WaitGroup
+	var repErr, senErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		delegation.Representative, repErr = district.Representative()
+	}()
+	go func() {
+		defer wg.Done()
+		delegation.Senators, senErr = district.Senators()
+	}()
+	wg.Wait()
+
+	if repErr != nil {
+		return nil, repErr
+	}
+	if senErr != nil {
+		return nil, senErr
+	}
+	return delegation, nil
+}
+
+// DelegationForLatLong returns the delegation for the district containing
+// a latitude and longitude.
+func DelegationForLatLong(latitude, longitude float64) (*Delegation, error) {
+	district, err := DistrictFromLatLong(latitude, longitude)
+	if err != nil {
+		return nil, err
+	}
+	return DelegationForDistrict(district)
+}
+
+// DelegationsForZip returns the delegations for every district that
+// contains part of a zip code.  Each delegation is fetched concurrently.
+func DelegationsForZip(zip string) ([]*Delegation, error) {
+	districts, err := DistrictsFromZip(zip)
+	if err != nil {
+		return nil, err
+	}
+
+	delegations := make([]*Delegation, len(districts))
+	errs := make([]error, len(districts))
+	var wg sync.WaitGroup
+	for i, district := range districts {
+		wg.Add(1)
+		go func(i int, district *District) {
+			defer wg.Done()
+			delegations[i], errs[i] = DelegationForDistrict(district)
+		}(i, district)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return delegations, nil
+}
@@ -0,0 +1,112 @@
+package gosunlight
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// delegationServer serves representatives, senators and zip code
+// districts, failing requests for Nevada's senators.
+func delegationServer(t *testing.T) *[]string {
+	representatives := map[string]string{
+		"WY-0": "Cheney", "DC-0": "Norton", "NY-10": "Nadler", "NY-12": "Maloney", "NV-1": "Titus",
+	}
+	senators := map[string]string{
+		"WY": `{"legislator": {"lastname": "Barrasso"}}, {"legislator": {"lastname": "Lummis"}}`,
+		"NY": `{"legislator": {"lastname": "Schumer"}}, {"legislator": {"lastname": "Gillibrand"}}`,
+	}
+	zips := map[string]string{
+		"10001": `{"district": {"state": "NY", "number": "10"}}, {"district": {"state": "NY", "number": "12"}}`,
+		"89101": `{"district": {"state": "NY", "number": "12"}}, {"district": {"state": "NV", "number": "1"}}`,
+	}
+
+	var lock sync.Mutex
+	var requests []string
+	serve(t, func(req *Request) (string, error) {
+		lock.Lock()
+		requests = append(requests, req.API+"."+req.Method+" "+req.Params.Encode())
+		lock.Unlock()
+		p := req.Params
+		switch req.API + "." + req.Method {
+		case "legislators.get":
+			if name, ok := representatives[p.Get("state")+"-"+p.Get("district")]; ok {
+				return fmt.Sprintf(`{"response": {"legislator": {"lastname": %q}}}`, name), nil
+			}
+		case "legislators.getList":
+			if list, ok := senators[p.Get("state")]; ok && p.Get("title") == "Sen" {
+				return `{"response": {"legislators": [` + list + `]}}`, nil
+			}
+		case "districts.getDistrictsFromZip":
+			if list, ok := zips[p.Get("zip")]; ok {
+				return `{"response": {"districts": [` + list + `]}}`, nil
+			}
+		}
+		return "", errors.New("Unavailable")
+	})
+	return &requests
+}
+
+func TestDelegations(t *testing.T) {
+	tests := []struct {
+		name      string
+		district  *District
+		zip       string
+		want      []string
+		senators  int
+		atLarge   bool
+		nonVoting bool
+		err       bool
+	}{
+		{name: "at-large", district: &District{State: "WY", Number: "0"}, want: []string{"Cheney"}, senators: 2, atLarge: true},
+		{name: "non-voting", district: &District{State: "DC", Number: "0"}, want: []string{"Norton"}, nonVoting: true},
+		{name: "zip spanning districts", zip: "10001", want: []string{"Nadler", "Maloney"}, senators: 2},
+		{name: "failed senators", district: &District{State: "NV", Number: "1"}, err: true},
+		{name: "failed zip district", zip: "89101", err: true},
+		{name: "missing number", district: &District{State: "NY"}, err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requests := delegationServer(t)
+			var delegations []*Delegation
+			var err error
+			if test.zip != "" {
+				delegations, err = DelegationsForZip(test.zip)
+			} else {
+				var d *Delegation
+				if d, err = DelegationForDistrict(test.district); d != nil {
+					delegations = []*Delegation{d}
+				}
+			}
+
+			if test.err {
+				if err == nil || delegations != nil {
+					t.Errorf("got %v, %v, want an error", delegations, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(delegations) != len(test.want) {
+				t.Fatalf("got %v delegations, want %v", len(delegations), len(test.want))
+			}
+			for i, d := range delegations {
+				if d.Representative == nil || d.Representative.LastName != test.want[i] {
+					t.Errorf("delegation %v representative = %v, want %v", i, d.Representative, test.want[i])
+				}
+				if len(d.Senators) != test.senators || d.AtLarge() != test.atLarge || d.NonVoting() != test.nonVoting {
+					t.Errorf("delegation %v has %v senators, at-large %v, non-voting %v", d.District, len(d.Senators), d.AtLarge(), d.NonVoting())
+				}
+			}
+			if test.nonVoting {
+				for _, r := range *requests {
+					if r == "legislators.getList state=DC&title=Sen" {
+						t.Error("requested senators for a non-voting delegation")
+					}
+				}
+			}
+		})
+	}
+}
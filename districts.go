@@ -3,6 +3,8 @@ package gosunlight
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 var districtAPIS struct {
@@ -19,14 +21,158 @@ func init() {
 type District struct {
 	State  string `json:"state"`
 	Number string `json:"number"`
+}
+
+// DistrictKind distinguishes numbered districts from the seats that cover
+// an entire state or territory.
+type DistrictKind int
+
+const (
+	// DistrictNumbered is one of several numbered districts in a state.
+	DistrictNumbered DistrictKind = iota
+	// DistrictAtLarge is the single district of a state with one
+	// representative.
+	DistrictAtLarge
+	// DistrictDelegate is DC or a territory represented by a non-voting
+	// delegate.
+	DistrictDelegate
+	// DistrictResidentCommissioner is Puerto Rico, represented by a
+	// non-voting resident commissioner.
+	DistrictResidentCommissioner
+)
+
+// String implements fmt.Stringer for district kinds
+func (k DistrictKind) String() string {
+	switch k {
+	case DistrictAtLarge:
+		return "at-large"
+	case DistrictDelegate:
+		return "delegate"
+	case DistrictResidentCommissioner:
+		return "resident commissioner"
+	}
+	return "numbered"
+}
+
+// Voting reports whether the member elected from this kind of district
+// has a vote on the House floor.
+func (k DistrictKind) Voting() bool {
+	return k == DistrictNumbered || k == DistrictAtLarge
+}
+
+// Kind returns the kind of district.  Sunlight numbers at-large seats, and
+// the seats for DC and the territories, as district "0".
+func (d District) Kind() DistrictKind {
+	state := StateForAbbreviation(d.State)
+	if state != nil && state.Territory {
+		if d.State == "PR" {
+			return DistrictResidentCommissioner
+		}
+		return DistrictDelegate
+	}
+	if state != nil && state.SingleSeat() {
+		return DistrictAtLarge
+	}
+	return DistrictNumbered
+}
+
+// number returns the district number, with seats covering an entire state
+// or territory as 0.
+func (d District) number() (int, error) {
+	if d.Kind() != DistrictNumbered {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(d.Number)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("Invalid number %q for district in %v", d.Number, d.State)
+	}
+	return n, nil
+}
+
+// String implements fmt.Stringer for districts.  Numbered districts are
+// formatted as "NY-03"; seats covering an entire state or territory are
+// formatted as "WY-AL" or "PR-AL".
+func (d District) String() string {
+	if d.Kind() != DistrictNumbered {
+		return d.State + "-AL"
+	}
+	n, err := d.number()
+	if err != nil {
+		return d.State + "-" + d.Number
+	}
+	return fmt.Sprintf("%v-%02d", d.State, n)
+}
+
+// GEOID returns the Census Bureau identifier for the district: the state
+// FIPS code followed by the district number.  At-large seats are numbered
+// "00", and the seats for DC and the territories "98".
+func (d District) GEOID() (string, error) {
+	state := StateForAbbreviation(d.State)
+	if state == nil {
+		return "", fmt.Errorf("Unknown state %q", d.State)
+	}
+	switch d.Kind() {
+	case DistrictDelegate, DistrictResidentCommissioner:
+		return state.FIPS + "98", nil
+	}
+	n, err := d.number()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v%02d", state.FIPS, n), nil
+}
 
-	rep      *Legislator
-	senators []*Legislator
+// ParseDistrict parses a district in the format returned by
+// District.String, such as "NY-03", "NY-3", "WY-AL" or "PR-AL".  The seat
+// of a state or territory with a single seat may also be given as "0" or
+// "00", and only those states and territories have such a seat.
+func ParseDistrict(s string) (*District, error) {
+	parts := strings.SplitN(s, "-", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("Invalid district %q", s)
+	}
+	state := StateForAbbreviation(strings.ToUpper(parts[0]))
+	if state == nil {
+		return nil, fmt.Errorf("Invalid district %q: unknown state", s)
+	}
+	if state.SingleSeat() {
+		switch strings.ToUpper(parts[1]) {
+		case "AL", "0", "00":
+			return &District{State: state.Abbreviation, Number: "0"}, nil
+		}
+		return nil, fmt.Errorf("Invalid district %q: %v has a single seat", s, state.Name)
+	}
+	n, err := strconv.Atoi(parts[1])
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("Invalid district %q: bad number", s)
+	}
+	return &District{State: state.Abbreviation, Number: strconv.Itoa(n)}, nil
 }
 
-// String implements fmt.Stringer for districts
-func (district District) String() string {
-	return fmt.Sprintf("%v-%02v", district.State, district.Number)
+// DistrictForGEOID returns the district for a four digit Census Bureau
+// district identifier, such as "3603" or "5600".
+func DistrictForGEOID(geoid string) (*District, error) {
+	if len(geoid) != 4 {
+		return nil, fmt.Errorf("Invalid district GEOID %q", geoid)
+	}
+	state := StateForFIPS(geoid[:2])
+	if state == nil {
+		return nil, fmt.Errorf("Invalid district GEOID %q: unknown state", geoid)
+	}
+	n, err := strconv.Atoi(geoid[2:])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid district GEOID %q: bad number", geoid)
+	}
+	if n == 98 && state.Territory {
+		n = 0
+	}
+	if state.SingleSeat() && n != 0 {
+		return nil, fmt.Errorf("Invalid district GEOID %q: %v has a single seat", geoid, state.Name)
+	}
+	if !state.SingleSeat() && n == 0 {
+		return nil, fmt.Errorf("Invalid district GEOID %q: %v has no at-large seat", geoid, state.Name)
+	}
+	return &District{State: state.Abbreviation, Number: strconv.Itoa(n)}, nil
 }
 
 // DistrictsFromZip returns a list of districts for a given zip code.  Because
@@ -76,36 +222,50 @@ func districtFromLatLong(latitude, longitude float64, districts int) (*District,
 
 // Representative returns the house of representatives member for a given
 // district.  This function will block while the data is fetched from
-// sunlight.  Subsequent calls return a cached value.
+// sunlight.  Subsequent calls, for this or any other copy of the district,
+// return a cached value until it expires or is invalidated.
 func (d *District) Representative() (*Legislator, error) {
-	if d.rep == nil {
-		if d.State == "" || d.Number == "" {
-			return nil, errors.New("State or number missing from district; cannot get legislators")
-		}
-		legislator, err := LegislatorGet(Legislator{State: d.State, District: d.Number})
+	if d.State == "" || d.Number == "" {
+		return nil, errors.New("State or number missing from district; cannot get legislators")
+	}
+	return districtRepresentative.get(d.String(), func() (*Legislator, error) {
+		n, err := d.number()
 		if err != nil {
 			return nil, err
 		}
-		d.rep = legislator
-	}
-	return d.rep, nil
+		return LegislatorGet(Legislator{State: d.State, District: strconv.Itoa(n)})
+	})
 }
 
 // Senators return the senators for a given district.  This function will
-// block while the data is fetched from sunlight.  Subsequent calls return
-// a cached value.
-func (d *District) Sentators() ([]*Legislator, error) {
-	if d.senators == nil {
-		if d.State == "" {
-			return nil, errors.New("State missing from district; cannot get senators")
-		}
-		legislators, err := LegislatorGetList(&Legislator{Title: "Sen", State: d.State})
-		if err != nil {
-			return nil, err
-		}
-		d.senators = legislators
+// block while the data is fetched from sunlight.  Subsequent calls for any
+// district in the same state return a cached value until it expires or is
+// invalidated.  DC and the territories have no senators, so their
+// districts return an empty list.
+func (d *District) Senators() ([]*Legislator, error) {
+	if d.State == "" {
+		return nil, errors.New("State missing from district; cannot get senators")
 	}
-	return d.senators, nil
+	if !d.Kind().Voting() {
+		return []*Legislator{}, nil
+	}
+	return stateSenators.get(d.State, func() ([]*Legislator, error) {
+		return LegislatorGetList(&Legislator{Title: "Sen", State: d.State})
+	})
+}
+
+// Invalidate discards the representative and senators cached for this
+// district, so that they are fetched again on next use.
+func (d *District) Invalidate() {
+	districtRepresentative.forget(d.String())
+	stateSenators.forget(d.State)
+}
+
+// Sentators is a misspelled alias of Senators, kept for compatibility.
+//
+// Deprecated: use Senators.
+func (d *District) Sentators() ([]*Legislator, error) {
+	return d.Senators()
 }
 
 type districtResponse struct {
@@ -0,0 +1,68 @@
+package gosunlight
+
+import "testing"
+
+func TestDistrictRoundTrip(t *testing.T) {
+	tests := []struct {
+		in    string
+		out   string
+		geoid string
+		kind  DistrictKind
+	}{
+		{"NY-3", "NY-03", "3603", DistrictNumbered},
+		{"ny-12", "NY-12", "3612", DistrictNumbered},
+		{"WY-AL", "WY-AL", "5600", DistrictAtLarge},
+		{"VT-0", "VT-AL", "5000", DistrictAtLarge},
+		{"DC-AL", "DC-AL", "1198", DistrictDelegate},
+		{"PR-AL", "PR-AL", "7298", DistrictResidentCommissioner},
+		{"GU-0", "GU-AL", "6698", DistrictDelegate},
+		{"AK-00", "AK-AL", "0200", DistrictAtLarge},
+	}
+	for _, test := range tests {
+		d, err := ParseDistrict(test.in)
+		if err != nil {
+			t.Errorf("ParseDistrict(%q): %v", test.in, err)
+			continue
+		}
+		if d.String() != test.out {
+			t.Errorf("ParseDistrict(%q) = %v, want %v", test.in, d, test.out)
+		}
+		if d.Kind() != test.kind {
+			t.Errorf("%v.Kind() = %v, want %v", d, d.Kind(), test.kind)
+		}
+		geoid, err := d.GEOID()
+		if err != nil || geoid != test.geoid {
+			t.Errorf("%v.GEOID() = %q, %v, want %q", d, geoid, err, test.geoid)
+		}
+		back, err := DistrictForGEOID(geoid)
+		if err != nil || back.String() != test.out {
+			t.Errorf("DistrictForGEOID(%q) = %v, %v, want %v", geoid, back, err, test.out)
+		}
+	}
+}
+
+func TestParseDistrictErrors(t *testing.T) {
+	for _, in := range []string{"NY", "XX-01", "NY-AB", "PR-02", "NY-0", "NY-00", "NY-AL", "NY-3x", "WY-01", "VT-AB"} {
+		if d, err := ParseDistrict(in); err == nil {
+			t.Errorf("ParseDistrict(%q) = %v, want error", in, d)
+		}
+	}
+}
+
+func TestDistrictForGEOIDErrors(t *testing.T) {
+	for _, geoid := range []string{"360", "9901", "36AB", "3600", "5601", "7201"} {
+		if d, err := DistrictForGEOID(geoid); err == nil {
+			t.Errorf("DistrictForGEOID(%q) = %v, want error", geoid, d)
+		}
+	}
+}
+
+func TestDistrictBadNumber(t *testing.T) {
+	d := District{State: "NY", Number: "x"}
+	if d.Kind() != DistrictNumbered || d.String() != "NY-x" {
+		t.Errorf("got %v district %v, want numbered NY-x", d.Kind(), d)
+	}
+	if geoid, err := d.GEOID(); err == nil {
+		t.Errorf("GEOID() = %q, want error", geoid)
+	}
+}
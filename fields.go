@@ -0,0 +1,164 @@
+package gosunlight
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// legislatorFields maps the json name of each Legislator field to its
+// index in the struct.
+var legislatorFields = make(map[string]int)
+
+func init() {
+	t := reflect.TypeOf(Legislator{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			legislatorFields[name] = i
+		}
+	}
+}
+
+// FieldSet is a set of Legislator fields to fetch, created with Fields.
+// Its methods mirror the legislator lookups, but request only the fields
+// in the set with the upstream fields parameter, and clear any other
+// fields the server returns anyway.  The zero FieldSet fetches every
+// field.
+//
+// The bioguide_id field is always fetched, as relations such as
+// Committees and Votes depend on it.
+type FieldSet struct {
+	names []string
+}
+
+// Fields returns the set of Legislator fields with the given json names,
+// such as "firstname" or "party".  It returns an error if a name is not
+// the json name of a Legislator field.
+func Fields(names ...string) (FieldSet, error) {
+	set := map[string]bool{"bioguide_id": true}
+	for _, name := range names {
+		if _, ok := legislatorFields[name]; !ok {
+			return FieldSet{}, fmt.Errorf("Unknown legislator field %q", name)
+		}
+		set[name] = true
+	}
+	fs := FieldSet{names: make([]string, 0, len(set))}
+	for name := range set {
+		fs.names = append(fs.names, name)
+	}
+	sort.Strings(fs.names)
+	return fs, nil
+}
+
+// MustFields is like Fields, but panics if a name is invalid.  It is meant
+// for initializing package level variables.
+func MustFields(names ...string) FieldSet {
+	fs, err := Fields(names...)
+	if err != nil {
+		panic(err)
+	}
+	return fs
+}
+
+// String implements fmt.Stringer for field sets
+func (fs FieldSet) String() string {
+	return strings.Join(fs.names, ",")
+}
+
+// Implementation of paramable for field sets
+func (fs FieldSet) addTo(query *url.Values) {
+	if len(fs.names) > 0 {
+		query.Add("fields", fs.String())
+	}
+}
+
+// Trim clears the fields of legislators that are not in the set.
+func (fs FieldSet) Trim(legislators ...*Legislator) {
+	if len(fs.names) == 0 {
+		return
+	}
+	keep := make(map[int]bool, len(fs.names))
+	for _, name := range fs.names {
+		keep[legislatorFields[name]] = true
+	}
+	for _, l := range legislators {
+		if l == nil {
+			continue
+		}
+		v := reflect.ValueOf(l).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if !keep[i] {
+				v.Field(i).Set(reflect.Zero(v.Field(i).Type()))
+			}
+		}
+	}
+}
+
+// trimmed trims the legislators returned by a lookup.
+func (fs FieldSet) trimmed(legislators []*Legislator, err error) ([]*Legislator, error) {
+	if err != nil {
+		return nil, err
+	}
+	fs.Trim(legislators...)
+	return legislators, nil
+}
+
+// LegislatorGet is LegislatorGet, fetching only the fields in the set.
+func (fs FieldSet) LegislatorGet(legislator Legislator) (*Legislator, error) {
+	l, err := getLegislator(false, legislator, fs)
+	if err != nil {
+		return nil, err
+	}
+	fs.Trim(l)
+	return l, nil
+}
+
+// LegislatorGetAll is LegislatorGetAll, fetching only the fields in the
+// set.
+func (fs FieldSet) LegislatorGetAll(legislator Legislator) (*Legislator, error) {
+	l, err := getLegislator(true, legislator, fs)
+	if err != nil {
+		return nil, err
+	}
+	fs.Trim(l)
+	return l, nil
+}
+
+// LegislatorGetList is LegislatorGetList, fetching only the fields in the
+// set.
+func (fs FieldSet) LegislatorGetList(legislators ...*Legislator) ([]*Legislator, error) {
+	return fs.trimmed(getLegislators(false, legislators, fs))
+}
+
+// LegislatorGetListAll is LegislatorGetListAll, fetching only the fields
+// in the set.
+func (fs FieldSet) LegislatorGetListAll(legislators ...*Legislator) ([]*Legislator, error) {
+	return fs.trimmed(getLegislators(true, legislators, fs))
+}
+
+// LegislatorSearch is LegislatorSearch, fetching only the fields in the
+// set.
+func (fs FieldSet) LegislatorSearch(name string) ([]*Legislator, error) {
+	return fs.trimmed(legislatorSearch(name, false, fs))
+}
+
+// LegislatorSearchAll is LegislatorSearchAll, fetching only the fields in
+// the set.
+func (fs FieldSet) LegislatorSearchAll(name string) ([]*Legislator, error) {
+	return fs.trimmed(legislatorSearch(name, true, fs))
+}
+
+// LegislatorsForZip is LegislatorsForZip, fetching only the fields in the
+// set.
+func (fs FieldSet) LegislatorsForZip(zip string) ([]*Legislator, error) {
+	return fs.trimmed(legislatorsForZip(zip, fs))
+}
+
+// LegislatorsForLatLong is LegislatorsForLatLong, fetching only the fields
+// in the set.
+func (fs FieldSet) LegislatorsForLatLong(latitude, longitude float64) ([]*Legislator, error) {
+	return fs.trimmed(legislatorsForLatLong(latitude, longitude, fs))
+}
@@ -0,0 +1,36 @@
+package gosunlight
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestFields(t *testing.T) {
+	fs, err := Fields("party", "firstname", "state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := url.Values{}
+	fs.addTo(&query)
+	if query.Get("fields") != "bioguide_id,firstname,party,state" {
+		t.Errorf("fields = %q", query.Get("fields"))
+	}
+	if _, err := Fields("frist_name"); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+
+	query = url.Values{}
+	FieldSet{}.addTo(&query)
+	if len(query) != 0 {
+		t.Errorf("zero FieldSet added %v", query)
+	}
+}
+
+func TestFieldsTrim(t *testing.T) {
+	l := &Legislator{BioguideID: "P000197", FirstName: "Nancy", Party: "D", State: "CA", Phone: "202-225-4965", InOffice: true}
+	MustFields("firstname", "party").Trim(l)
+	want := Legislator{BioguideID: "P000197", FirstName: "Nancy", Party: "D"}
+	if *l != want {
+		t.Errorf("trimmed legislator = %+v, want %+v", *l, want)
+	}
+}
@@ -0,0 +1,562 @@
+package gosunlight
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FinanceData holds campaign finance records loaded from FEC and
+// OpenSecrets bulk data files, and links them to legislators by their
+// FECId and CRPID.
+//
+// FEC files record who gave to which committee, and are linked to a
+// legislator through their candidate id and campaign committees.
+// OpenSecrets files record the same contributions with the recipient's
+// CRP id and an industry code, and are needed for TopIndustries.  When
+// both are loaded for a legislator and cycle, the OpenSecrets records are
+// used, so contributions are not counted twice.
+//
+// See: https://www.fec.gov/data/browse-data/?tab=bulk-data
+// See: https://www.opensecrets.org/bulk-data
+type FinanceData struct {
+	candidates map[string]*FECCandidate
+	committees map[string]*FECCommittee
+
+	// candidateCommittees lists the authorized committees of each
+	// candidate.
+	candidateCommittees map[string][]string
+	categories          map[string]*IndustryCategory
+
+	byCommittee map[string][]*Contribution
+	byCRPID     map[string][]*Contribution
+}
+
+// FECCandidate is a candidate from an FEC candidate master file.
+type FECCandidate struct {
+	ID           string
+	Name         string
+	Party        string
+	ElectionYear int
+	State        string
+	// Office is "H", "S" or "P".
+	Office   string
+	District string
+	// PrincipalCommittee is the id of the candidate's principal campaign
+	// committee.
+	PrincipalCommittee string
+}
+
+// FECCommittee is a committee from an FEC committee master file.
+type FECCommittee struct {
+	ID    string
+	Name  string
+	Party string
+	// Designation is "P" for a principal campaign committee and "A" for
+	// other committees authorized by a candidate.
+	Designation string
+	Type        string
+	CandidateID string
+}
+
+// IndustryCategory is an OpenSecrets category code, and the industry and
+// sector it belongs to.
+type IndustryCategory struct {
+	Code     string
+	Name     string
+	Industry string
+	Sector   string
+}
+
+// Contribution is a single contribution from an individual.
+type Contribution struct {
+	Cycle int
+
+	// CommitteeID is set for FEC records, and RecipientCRPID for
+	// OpenSecrets records.
+	CommitteeID    string
+	RecipientCRPID string
+
+	Name       string
+	City       string
+	State      string
+	Zip        string
+	Employer   string
+	Occupation string
+	Date       time.Time
+	Amount     float64
+
+	// IndustryCode is the OpenSecrets category code of the contributor,
+	// if known.
+	IndustryCode string
+}
+
+// zip5 returns the first five digits of the contributor's zip code.
+func (c *Contribution) zip5() string {
+	if len(c.Zip) > 5 {
+		return c.Zip[:5]
+	}
+	return c.Zip
+}
+
+// CycleTotal is the money a legislator raised from individuals in a two
+// year election cycle.
+type CycleTotal struct {
+	Cycle         int
+	Total         float64
+	Contributions int
+}
+
+// ContributorTotal is the money a single contributor gave.  Contributors
+// are identified by name and five digit zip code.
+type ContributorTotal struct {
+	Name          string
+	Zip           string
+	Employer      string
+	Total         float64
+	Contributions int
+}
+
+// IndustryTotal is the money given by contributors in an industry.
+type IndustryTotal struct {
+	Industry      string
+	Sector        string
+	Total         float64
+	Contributions int
+}
+
+// NewFinanceData returns an empty set of campaign finance records.
+func NewFinanceData() *FinanceData {
+	return &FinanceData{
+		candidates:          make(map[string]*FECCandidate),
+		committees:          make(map[string]*FECCommittee),
+		candidateCommittees: make(map[string][]string),
+		categories:          make(map[string]*IndustryCategory),
+		byCommittee:         make(map[string][]*Contribution),
+		byCRPID:             make(map[string][]*Contribution),
+	}
+}
+
+// LoadFECCandidates reads an FEC candidate master file (cn.txt).
+func (f *FinanceData) LoadFECCandidates(r io.Reader) error {
+	return readPipeDelimited(r, 15, func(fields []string) error {
+		year, _ := strconv.Atoi(fields[3])
+		c := &FECCandidate{
+			ID:                 fields[0],
+			Name:               fields[1],
+			Party:              fields[2],
+			ElectionYear:       year,
+			State:              fields[4],
+			Office:             fields[5],
+			District:           fields[6],
+			PrincipalCommittee: fields[9],
+		}
+		f.candidates[c.ID] = c
+		if c.PrincipalCommittee != "" {
+			f.addCandidateCommittee(c.ID, c.PrincipalCommittee)
+		}
+		return nil
+	})
+}
+
+// LoadFECCommittees reads an FEC committee master file (cm.txt).
+func (f *FinanceData) LoadFECCommittees(r io.Reader) error {
+	return readPipeDelimited(r, 15, func(fields []string) error {
+		c := &FECCommittee{
+			ID:          fields[0],
+			Name:        fields[1],
+			Designation: fields[8],
+			Type:        fields[9],
+			Party:       fields[10],
+			CandidateID: fields[14],
+		}
+		f.committees[c.ID] = c
+		if c.CandidateID != "" && (c.Designation == "P" || c.Designation == "A") {
+			f.addCandidateCommittee(c.CandidateID, c.ID)
+		}
+		return nil
+	})
+}
+
+func (f *FinanceData) addCandidateCommittee(candidateID, committeeID string) {
+	for _, id := range f.candidateCommittees[candidateID] {
+		if id == committeeID {
+			return
+		}
+	}
+	f.candidateCommittees[candidateID] = append(f.candidateCommittees[candidateID], committeeID)
+}
+
+// LoadFECContributions reads an FEC individual contributions file
+// (itcont.txt) for a two year election cycle, such as 2020.  Memo entries,
+// which repeat contributions reported elsewhere, are skipped.
+func (f *FinanceData) LoadFECContributions(cycle int, r io.Reader) error {
+	return readPipeDelimited(r, 21, func(fields []string) error {
+		if fields[18] == "X" {
+			return nil
+		}
+		amount, err := strconv.ParseFloat(fields[14], 64)
+		if err != nil {
+			return fmt.Errorf("invalid amount %q", fields[14])
+		}
+		var date time.Time
+		if fields[13] != "" {
+			if date, err = time.Parse("01022006", fields[13]); err != nil {
+				return fmt.Errorf("invalid date %q", fields[13])
+			}
+		}
+		c := &Contribution{
+			Cycle:       cycle,
+			CommitteeID: fields[0],
+			Name:        fields[7],
+			City:        fields[8],
+			State:       fields[9],
+			Zip:         fields[10],
+			Employer:    fields[11],
+			Occupation:  fields[12],
+			Date:        date,
+			Amount:      amount,
+		}
+		f.byCommittee[c.CommitteeID] = append(f.byCommittee[c.CommitteeID], c)
+		return nil
+	})
+}
+
+// LoadCRPCategories reads the OpenSecrets category file
+// (CRP_Categories.txt), which names the industry and sector of each
+// category code.
+func (f *FinanceData) LoadCRPCategories(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		// The file begins with notes and a header row.
+		if len(fields) < 5 || fields[0] == "Catcode" {
+			continue
+		}
+		c := &IndustryCategory{
+			Code:     strings.TrimSpace(fields[0]),
+			Name:     strings.TrimSpace(fields[1]),
+			Industry: strings.TrimSpace(fields[3]),
+			Sector:   strings.TrimSpace(fields[4]),
+		}
+		f.categories[strings.ToUpper(c.Code)] = c
+	}
+	return scanner.Err()
+}
+
+// LoadOpenSecretsIndividuals reads an OpenSecrets individual contributions
+// file (indivs20.txt).  Only contributions to candidates, whose recipient
+// ids begin with "N", are kept.
+func (f *FinanceData) LoadOpenSecretsIndividuals(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if scanner.Text() == "" {
+			continue
+		}
+		fields, err := splitOpenSecretsLine(scanner.Text())
+		if err == nil && len(fields) < 18 {
+			err = errors.New("too few columns")
+		}
+		if err != nil {
+			return fmt.Errorf("OpenSecrets file line %v: %v", line, err)
+		}
+		if !strings.HasPrefix(fields[4], "N") {
+			continue
+		}
+		cycle, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return fmt.Errorf("OpenSecrets file line %v: invalid cycle %q", line, fields[0])
+		}
+		amount, err := strconv.ParseFloat(fields[9], 64)
+		if err != nil {
+			return fmt.Errorf("OpenSecrets file line %v: invalid amount %q", line, fields[9])
+		}
+		var date time.Time
+		if fields[8] != "" {
+			if date, err = time.Parse("01/02/2006", fields[8]); err != nil {
+				return fmt.Errorf("OpenSecrets file line %v: invalid date %q", line, fields[8])
+			}
+		}
+		c := &Contribution{
+			Cycle:          cycle,
+			RecipientCRPID: fields[4],
+			Name:           fields[3],
+			City:           fields[11],
+			State:          fields[12],
+			Zip:            fields[13],
+			Date:           date,
+			Amount:         amount,
+			IndustryCode:   strings.ToUpper(fields[7]),
+		}
+		if len(fields) > 21 {
+			c.Occupation, c.Employer = fields[20], fields[21]
+		}
+		f.byCRPID[c.RecipientCRPID] = append(f.byCRPID[c.RecipientCRPID], c)
+	}
+	return scanner.Err()
+}
+
+// LoadFECCandidatesFile reads an FEC candidate master file from disk.
+func (f *FinanceData) LoadFECCandidatesFile(path string) error {
+	return loadFile(path, f.LoadFECCandidates)
+}
+
+// LoadFECCommitteesFile reads an FEC committee master file from disk.
+func (f *FinanceData) LoadFECCommitteesFile(path string) error {
+	return loadFile(path, f.LoadFECCommittees)
+}
+
+// LoadFECContributionsFile reads an FEC individual contributions file for
+// a cycle from disk.
+func (f *FinanceData) LoadFECContributionsFile(cycle int, path string) error {
+	return loadFile(path, func(r io.Reader) error {
+		return f.LoadFECContributions(cycle, r)
+	})
+}
+
+// LoadCRPCategoriesFile reads the OpenSecrets category file from disk.
+func (f *FinanceData) LoadCRPCategoriesFile(path string) error {
+	return loadFile(path, f.LoadCRPCategories)
+}
+
+// LoadOpenSecretsIndividualsFile reads an OpenSecrets individual
+// contributions file from disk.
+func (f *FinanceData) LoadOpenSecretsIndividualsFile(path string) error {
+	return loadFile(path, f.LoadOpenSecretsIndividuals)
+}
+
+// Candidate returns the FEC candidate record for a legislator, or nil if
+// none is loaded.
+func (f *FinanceData) Candidate(l *Legislator) *FECCandidate {
+	return f.candidates[l.FECId]
+}
+
+// Committees returns a legislator's authorized campaign committees that
+// have been loaded from an FEC committee master file.
+func (f *FinanceData) Committees(l *Legislator) []*FECCommittee {
+	var committees []*FECCommittee
+	for _, id := range f.candidateCommittees[l.FECId] {
+		if c, ok := f.committees[id]; ok {
+			committees = append(committees, c)
+		}
+	}
+	return committees
+}
+
+// Contributions returns the contributions to a legislator in a cycle, or
+// in every cycle if cycle is 0, ordered by date.
+func (f *FinanceData) Contributions(l *Legislator, cycle int) []*Contribution {
+	fec := make(map[int][]*Contribution)
+	if l.FECId != "" {
+		for _, id := range f.candidateCommittees[l.FECId] {
+			for _, c := range f.byCommittee[id] {
+				fec[c.Cycle] = append(fec[c.Cycle], c)
+			}
+		}
+	}
+	crp := make(map[int][]*Contribution)
+	if l.CRPID != "" {
+		for _, c := range f.byCRPID[l.CRPID] {
+			crp[c.Cycle] = append(crp[c.Cycle], c)
+		}
+	}
+
+	var contributions []*Contribution
+	for year, list := range crp {
+		if cycle == 0 || year == cycle {
+			contributions = append(contributions, list...)
+		}
+	}
+	for year, list := range fec {
+		if _, ok := crp[year]; ok {
+			// Already counted from the OpenSecrets records.
+			continue
+		}
+		if cycle == 0 || year == cycle {
+			contributions = append(contributions, list...)
+		}
+	}
+	sort.SliceStable(contributions, func(i, j int) bool {
+		return contributions[i].Date.Before(contributions[j].Date)
+	})
+	return contributions
+}
+
+// CycleTotals returns the money a legislator raised from individuals in
+// each cycle with loaded contributions, ordered by cycle.
+func (f *FinanceData) CycleTotals(l *Legislator) []CycleTotal {
+	byCycle := make(map[int]*CycleTotal)
+	for _, c := range f.Contributions(l, 0) {
+		total, ok := byCycle[c.Cycle]
+		if !ok {
+			total = &CycleTotal{Cycle: c.Cycle}
+			byCycle[c.Cycle] = total
+		}
+		total.Total += c.Amount
+		total.Contributions++
+	}
+	totals := make([]CycleTotal, 0, len(byCycle))
+	for _, total := range byCycle {
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		return totals[i].Cycle < totals[j].Cycle
+	})
+	return totals
+}
+
+// TopContributors returns the n contributors who gave the most to a
+// legislator in a cycle, or in every cycle if cycle is 0.
+func (f *FinanceData) TopContributors(l *Legislator, cycle, n int) []ContributorTotal {
+	byContributor := make(map[string]*ContributorTotal)
+	for _, c := range f.Contributions(l, cycle) {
+		name := strings.ToUpper(strings.TrimSpace(c.Name))
+		key := name + "|" + c.zip5()
+		total, ok := byContributor[key]
+		if !ok {
+			total = &ContributorTotal{Name: name, Zip: c.zip5()}
+			byContributor[key] = total
+		}
+		if c.Employer != "" {
+			total.Employer = c.Employer
+		}
+		total.Total += c.Amount
+		total.Contributions++
+	}
+	totals := make([]ContributorTotal, 0, len(byContributor))
+	for _, total := range byContributor {
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Total != totals[j].Total {
+			return totals[i].Total > totals[j].Total
+		}
+		return totals[i].Name < totals[j].Name
+	})
+	if n >= 0 && len(totals) > n {
+		totals = totals[:n]
+	}
+	return totals
+}
+
+// TopIndustries returns the n industries whose contributors gave the most
+// to a legislator in a cycle, or in every cycle if cycle is 0.  Only
+// contributions with a category code from LoadCRPCategories are counted.
+func (f *FinanceData) TopIndustries(l *Legislator, cycle, n int) []IndustryTotal {
+	byIndustry := make(map[string]*IndustryTotal)
+	for _, c := range f.Contributions(l, cycle) {
+		category, ok := f.categories[c.IndustryCode]
+		if !ok {
+			continue
+		}
+		total, ok := byIndustry[category.Industry]
+		if !ok {
+			total = &IndustryTotal{Industry: category.Industry, Sector: category.Sector}
+			byIndustry[category.Industry] = total
+		}
+		total.Total += c.Amount
+		total.Contributions++
+	}
+	totals := make([]IndustryTotal, 0, len(byIndustry))
+	for _, total := range byIndustry {
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Total != totals[j].Total {
+			return totals[i].Total > totals[j].Total
+		}
+		return totals[i].Industry < totals[j].Industry
+	})
+	if n >= 0 && len(totals) > n {
+		totals = totals[:n]
+	}
+	return totals
+}
+
+// InStateShare returns the share of the money a legislator raised in a
+// cycle, or in every cycle if cycle is 0, that came from contributors in
+// the legislator's state.  Along with the share, it returns the number of
+// contributions it was computed from; contributions without a state are
+// not counted.
+func (f *FinanceData) InStateShare(l *Legislator, cycle int) (float64, int) {
+	inState, total, count := 0.0, 0.0, 0
+	for _, c := range f.Contributions(l, cycle) {
+		if c.State == "" {
+			continue
+		}
+		count++
+		total += c.Amount
+		if c.State == l.State {
+			inState += c.Amount
+		}
+	}
+	if count == 0 || total == 0 {
+		return 0, count
+	}
+	return inState / total, count
+}
+
+// readPipeDelimited calls fn with the fields of each line of an FEC bulk
+// data file, which has no header row and separates fields with "|".
+func readPipeDelimited(r io.Reader, columns int, fn func(fields []string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if scanner.Text() == "" {
+			continue
+		}
+		fields := strings.Split(scanner.Text(), "|")
+		if len(fields) < columns {
+			return fmt.Errorf("FEC file line %v: got %v columns, want %v", line, len(fields), columns)
+		}
+		if err := fn(fields); err != nil {
+			return fmt.Errorf("FEC file line %v: %v", line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// splitOpenSecretsLine splits a line of an OpenSecrets bulk data file,
+// where fields are separated by commas and text fields are quoted with
+// "|".
+func splitOpenSecretsLine(line string) ([]string, error) {
+	var fields []string
+	for {
+		var field string
+		if strings.HasPrefix(line, "|") {
+			end := strings.Index(line[1:], "|")
+			if end < 0 {
+				return nil, errors.New("unterminated field")
+			}
+			field, line = line[1:end+1], line[end+2:]
+		} else if comma := strings.Index(line, ","); comma >= 0 {
+			field, line = line[:comma], line[comma:]
+		} else {
+			field, line = line, ""
+		}
+		fields = append(fields, field)
+		if line == "" {
+			return fields, nil
+		}
+		if line[0] != ',' {
+			return nil, fmt.Errorf("unexpected %q after field", line[0])
+		}
+		line = line[1:]
+	}
+}
+
+func loadFile(path string, load func(io.Reader) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return load(f)
+}
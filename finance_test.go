@@ -0,0 +1,94 @@
+package gosunlight
+
+import (
+	"strings"
+	"testing"
+)
+
+const fecCandidates = `H8NY15148|OCASIO-CORTEZ, ALEXANDRIA|DEM|2020|NY|H|14|I|C|C00639591|PO BOX 1|||NY|10462
+`
+
+const fecCommittees = `C00639591|ALEXANDRIA OCASIO-CORTEZ FOR CONGRESS|TREASURER|PO BOX 1||BRONX|NY|10462|P|H|DEM|Q|||H8NY15148
+C00700000|AOC VICTORY FUND|TREASURER|PO BOX 1||BRONX|NY|10462|J|N|||||
+`
+
+const fecContributions = `C00639591|N|Q1|P2020|201904150000000001|15|IND|SMITH, JANE|BRONX|NY|104621234|ACME|ENGINEER|03012019|500||A1|1|||1
+C00639591|N|Q1|P2020|201904150000000002|15|IND|SMITH, JANE|BRONX|NY|10462|ACME|ENGINEER|03152019|250||A2|1|||2
+C00639591|N|Q1|P2020|201904150000000003|15|IND|DOE, JOHN|BOSTON|MA|02108|SELF|LAWYER|03202019|1000||A3|1|||3
+C00639591|N|Q1|P2020|201904150000000004|15|IND|DOE, JOHN|BOSTON|MA|02108|SELF|LAWYER|03202019|1000||A4|1|X|MEMO|4
+C00700000|N|Q1|P2020|201904150000000005|15|IND|ROE, RICH|MIAMI|FL|33101|BANK|BANKER|03202019|2800||A5|1|||5
+`
+
+const crpCategories = "Notes: CRP categories\n" +
+	"Catcode\tCatname\tCatorder\tIndustry\tSector\tSector Long\n" +
+	"K1000\tAttorneys & law firms\tK01\tLawyers/Law Firms\tLawyers & Lobbyists\tLawyers & Lobbyists\n" +
+	"H1100\tPhysicians\tH01\tHealth Professionals\tHealth\tHealth\n"
+
+const openSecretsIndividuals = `|2018|,|4011320181234|,|i3003123|,|DOE, JOHN|,|N00041162|,|Doe LLP|,|Doe LLP|,|K1000|,06/01/2018,2700,|1 State St|,|BOSTON|,|MA|,|02108|,|DW|,|15|,|C00639591|,||,|M|,|123|,|LAWYER|,|SELF|,|P|
+|2018|,|4011320181235|,|i3003124|,|ROE, RACHEL|,|N00041162|,||,||,|H1100|,06/02/2018,300,||,|BRONX|,|NY|,|10462|,|DW|,|15|,|C00639591|,||,|F|,|124|,|DOCTOR|,|HOSPITAL|,|P|
+|2018|,|4011320181236|,|i3003125|,|ROE, RACHEL|,|C00000000|,||,||,|H1100|,06/02/2018,300,||,|BRONX|,|NY|,|10462|,|PB|,|15|,|C00000000|,||,|F|,|125|,|DOCTOR|,|HOSPITAL|,|P|
+`
+
+func testFinanceData(t *testing.T) *FinanceData {
+	f := NewFinanceData()
+	for _, load := range []func() error{
+		func() error { return f.LoadFECCandidates(strings.NewReader(fecCandidates)) },
+		func() error { return f.LoadFECCommittees(strings.NewReader(fecCommittees)) },
+		func() error { return f.LoadFECContributions(2020, strings.NewReader(fecContributions)) },
+		func() error { return f.LoadCRPCategories(strings.NewReader(crpCategories)) },
+		func() error { return f.LoadOpenSecretsIndividuals(strings.NewReader(openSecretsIndividuals)) },
+	} {
+		if err := load(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return f
+}
+
+var financeLegislator = &Legislator{State: "NY", FECId: "H8NY15148", CRPID: "N00041162"}
+
+func TestCycleTotals(t *testing.T) {
+	f := testFinanceData(t)
+	totals := f.CycleTotals(financeLegislator)
+	if len(totals) != 2 {
+		t.Fatalf("got %v cycles, want 2", len(totals))
+	}
+	if totals[0] != (CycleTotal{2018, 3000, 2}) || totals[1] != (CycleTotal{2020, 1750, 3}) {
+		t.Errorf("CycleTotals = %+v", totals)
+	}
+	if committees := f.Committees(financeLegislator); len(committees) != 1 || committees[0].ID != "C00639591" {
+		t.Errorf("Committees = %v, want the principal campaign committee", committees)
+	}
+}
+
+func TestTopContributors(t *testing.T) {
+	f := testFinanceData(t)
+	top := f.TopContributors(financeLegislator, 2020, 1)
+	if len(top) != 1 || top[0].Name != "DOE, JOHN" || top[0].Total != 1000 {
+		t.Errorf("TopContributors = %+v", top)
+	}
+	all := f.TopContributors(financeLegislator, 2020, -1)
+	if len(all) != 2 || all[1].Name != "SMITH, JANE" || all[1].Total != 750 || all[1].Contributions != 2 {
+		t.Errorf("TopContributors = %+v", all)
+	}
+}
+
+func TestTopIndustries(t *testing.T) {
+	f := testFinanceData(t)
+	top := f.TopIndustries(financeLegislator, 0, 10)
+	if len(top) != 2 || top[0].Industry != "Lawyers/Law Firms" || top[1].Sector != "Health" {
+		t.Errorf("TopIndustries = %+v", top)
+	}
+}
+
+func TestInStateShare(t *testing.T) {
+	f := testFinanceData(t)
+	share, n := f.InStateShare(financeLegislator, 2020)
+	if n != 3 || share != 750.0/1750 {
+		t.Errorf("InStateShare = %v, %v; want %v, 3", share, n, 750.0/1750)
+	}
+	share, n = f.InStateShare(financeLegislator, 2018)
+	if n != 2 || share != 0.1 {
+		t.Errorf("InStateShare = %v, %v; want 0.1, 2", share, n)
+	}
+}
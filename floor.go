@@ -0,0 +1,307 @@
+package gosunlight
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+var floorAPIS struct {
+	getList sunlightAPI
+}
+
+func init() {
+	floorAPIS.getList = newSunlightAPI("floor_updates", "getList")
+}
+
+// FloorUpdate is a single update on the activity on the House or Senate
+// floor.
+type FloorUpdate struct {
+	Chamber        string   `json:"chamber"`
+	Congress       int      `json:"congress"`
+	Timestamp      string   `json:"timestamp"`
+	LegislativeDay string   `json:"legislative_day"`
+	Update         string   `json:"update"`
+	BillIDs        []string `json:"bill_ids"`
+	RollIDs        []string `json:"roll_ids"`
+	LegislatorIDs  []string `json:"legislator_ids"`
+}
+
+// String implements fmt.Stringer for floor updates
+func (u FloorUpdate) String() string {
+	return fmt.Sprintf("%v %v: %v", u.Timestamp, u.Chamber, u.Update)
+}
+
+// Time returns the time of the update.
+func (u FloorUpdate) Time() (time.Time, error) {
+	return time.Parse(time.RFC3339, u.Timestamp)
+}
+
+// key identifies an update among those with the same timestamp, which
+// floor updates have no id to do.
+func (u FloorUpdate) key() string {
+	sum := sha1.Sum([]byte(u.Chamber + "\n" + u.Timestamp + "\n" + u.Update))
+	return hex.EncodeToString(sum[:8])
+}
+
+// FloorUpdateGetList returns a page of floor updates for a chamber, newest
+// first.  Pages are numbered from 1 and hold PerPage updates.
+//
+// See: http://services.sunlightlabs.com/docs/congressapi/floor_updates.getList/
+func FloorUpdateGetList(chamber string, page int) ([]*FloorUpdate, error) {
+	return floorUpdateGetList(context.Background(), chamber, page)
+}
+
+func floorUpdateGetList(ctx context.Context, chamber string, page int) ([]*FloorUpdate, error) {
+	p := params{
+		"chamber": chamber,
+		"order":   "timestamp__desc",
+	}
+	return collectList(ctx, floorAPIS.getList, "floor_updates", (*floorUpdateItem).floorUpdate, p, pageParams(page))
+}
+
+// maxWatchPages limits how far back a FloorWatcher looks for updates it
+// has missed.
+const maxWatchPages = 10
+
+// FloorWatcher polls for new floor updates.
+type FloorWatcher struct {
+	// StateFile, if set, records the newest updates sent for each chamber,
+	// so that a restarted watcher does not send updates again.
+	StateFile string
+
+	// OnError, if set, is called with any error fetching updates or saving
+	// state.  Watching continues after an error.
+	OnError func(error)
+
+	lock  sync.Mutex
+	fetch func(ctx context.Context, chamber string, page int) ([]*FloorUpdate, error)
+}
+
+// Watch polls a chamber for floor updates every interval, sending each
+// new update, oldest first, on the returned channel.  Updates newer than
+// the last one sent are new, as are updates with the same timestamp that
+// were not already sent.  On the first run without saved state, the most
+// recent page of updates is sent.  The channel is closed when ctx is done.
+//
+// After a long outage, only the updates in the last maxWatchPages pages
+// are sent, and OnError is called with the time of the oldest update
+// sent, before which updates may have been missed.
+//
+// The interval must be positive.  Otherwise OnError is called and the
+// returned channel is closed without polling.
+func (w *FloorWatcher) Watch(ctx context.Context, chamber string, interval time.Duration) <-chan *FloorUpdate {
+	updates := make(chan *FloorUpdate)
+	if interval <= 0 {
+		w.error(fmt.Errorf("Cannot watch floor updates every %v: interval must be positive", interval))
+		close(updates)
+		return updates
+	}
+	go func() {
+		defer close(updates)
+		last, err := w.lastSeen(chamber)
+		if err != nil {
+			w.error(err)
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			fresh, err := w.poll(ctx, chamber, last)
+			if err != nil && ctx.Err() == nil {
+				w.error(err)
+			}
+			for _, u := range fresh {
+				select {
+				case updates <- u:
+				case <-ctx.Done():
+					return
+				}
+				t, _ := u.Time()
+				last = last.add(t, u.key())
+				if err := w.saveLastSeen(chamber, last); err != nil {
+					w.error(err)
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return updates
+}
+
+// poll returns the updates not yet sent, oldest first.  If the oldest
+// update not yet sent is beyond the pages it reads, it returns the
+// updates it read along with an error.
+func (w *FloorWatcher) poll(ctx context.Context, chamber string, last floorMark) ([]*FloorUpdate, error) {
+	type timedUpdate struct {
+		update *FloorUpdate
+		time   time.Time
+	}
+	var fresh []timedUpdate
+	done := false
+	for page := 1; page <= maxWatchPages && !done; page++ {
+		fetch := w.fetch
+		if fetch == nil {
+			fetch = floorUpdateGetList
+		}
+		updates, err := fetch(ctx, chamber, page)
+		if err != nil {
+			return nil, err
+		}
+		done = len(updates) < PerPage || last.Time.IsZero()
+		for _, u := range updates {
+			t, err := u.Time()
+			if err != nil {
+				continue
+			}
+			if t.Before(last.Time) {
+				done = true
+				continue
+			}
+			if last.sent(t, u.key()) {
+				continue
+			}
+			fresh = append(fresh, timedUpdate{u, t})
+		}
+	}
+
+	// Updates arrive newest first, so reversing them keeps updates with
+	// the same timestamp in the order they were posted.
+	slices.Reverse(fresh)
+	sort.SliceStable(fresh, func(i, j int) bool {
+		return fresh[i].time.Before(fresh[j].time)
+	})
+	updates := make([]*FloorUpdate, len(fresh))
+	for i, f := range fresh {
+		updates[i] = f.update
+	}
+	if !done && len(updates) > 0 {
+		return updates, fmt.Errorf("More than %v pages of %v floor updates since the last poll; updates before %v may have been missed",
+			maxWatchPages, chamber, updates[0].Timestamp)
+	}
+	return updates, nil
+}
+
+// floorMark records the newest floor updates sent for a chamber: their
+// timestamp, and the keys of every update sent with that timestamp.
+type floorMark struct {
+	Time time.Time `json:"time"`
+	Keys []string  `json:"keys"`
+}
+
+// sent reports whether the update with a time and key has been sent.
+func (m floorMark) sent(t time.Time, key string) bool {
+	if t.Before(m.Time) {
+		return true
+	}
+	if t.Equal(m.Time) {
+		for _, k := range m.Keys {
+			if k == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// add returns the mark after sending the update with a time and key.
+func (m floorMark) add(t time.Time, key string) floorMark {
+	switch {
+	case t.After(m.Time):
+		return floorMark{Time: t, Keys: []string{key}}
+	case t.Equal(m.Time):
+		return floorMark{Time: m.Time, Keys: append(append([]string(nil), m.Keys...), key)}
+	}
+	return m
+}
+
+// UnmarshalJSON reads a mark, or a bare time from state files written
+// before marks recorded keys.
+func (m *floorMark) UnmarshalJSON(data []byte) error {
+	var t time.Time
+	if err := json.Unmarshal(data, &t); err == nil {
+		*m = floorMark{Time: t}
+		return nil
+	}
+	type plain floorMark
+	return json.Unmarshal(data, (*plain)(m))
+}
+
+func (w *FloorWatcher) error(err error) {
+	if w.OnError != nil {
+		w.OnError(err)
+	}
+}
+
+// readState reads the newest updates sent for each chamber.
+func (w *FloorWatcher) readState() (map[string]floorMark, error) {
+	state := make(map[string]floorMark)
+	if w.StateFile == "" {
+		return state, nil
+	}
+	data, err := os.ReadFile(w.StateFile)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return make(map[string]floorMark), fmt.Errorf("Invalid floor watcher state %v: %v", w.StateFile, err)
+	}
+	return state, nil
+}
+
+func (w *FloorWatcher) lastSeen(chamber string) (floorMark, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	state, err := w.readState()
+	return state[chamber], err
+}
+
+// saveLastSeen records the newest updates sent for a chamber.  The state
+// file is replaced atomically.
+func (w *FloorWatcher) saveLastSeen(chamber string, last floorMark) error {
+	if w.StateFile == "" {
+		return nil
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	state, err := w.readState()
+	if err != nil {
+		return err
+	}
+	state[chamber] = last
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp := w.StateFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, w.StateFile)
+}
+
+// floorUpdateItem is an element of a list of floor updates.
+type floorUpdateItem struct {
+	FloorUpdate *FloorUpdate `json:"floor_update"`
+}
+
+func (i *floorUpdateItem) floorUpdate() *FloorUpdate {
+	return i.FloorUpdate
+}
+
+func (i *floorUpdateItem) into(u *FloorUpdate) {
+	i.FloorUpdate = u
+}
@@ -0,0 +1,157 @@
+package gosunlight
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeFloor serves floor updates, newest first, one per page.
+type fakeFloor struct {
+	lock    sync.Mutex
+	updates []*FloorUpdate
+}
+
+func (f *fakeFloor) add(timestamp, text string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	u := &FloorUpdate{Chamber: "house", Timestamp: timestamp, Update: text}
+	f.updates = append([]*FloorUpdate{u}, f.updates...)
+}
+
+func (f *fakeFloor) fetch(ctx context.Context, chamber string, page int) ([]*FloorUpdate, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if page > len(f.updates) {
+		return nil, nil
+	}
+	return f.updates[page-1 : page], nil
+}
+
+func receive(t *testing.T, updates <-chan *FloorUpdate, want ...string) {
+	for _, text := range want {
+		select {
+		case u := <-updates:
+			if u.Update != text {
+				t.Errorf("got update %q, want %q", u.Update, text)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %q", text)
+		}
+	}
+}
+
+func TestFloorWatcher(t *testing.T) {
+	defer func(perPage int) { PerPage = perPage }(PerPage)
+	PerPage = 1
+	floor := &fakeFloor{}
+	floor.add("2013-01-03T12:00:00Z", "first")
+	state := filepath.Join(t.TempDir(), "floor.json")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &FloorWatcher{StateFile: state, fetch: floor.fetch}
+	updates := w.Watch(ctx, "house", 5*time.Millisecond)
+	receive(t, updates, "first")
+	floor.add("2013-01-03T12:05:00Z", "second")
+	floor.add("2013-01-03T12:10:00Z", "third")
+	receive(t, updates, "second", "third")
+	cancel()
+	for range updates {
+	}
+
+	// A restarted watcher only sends updates newer than the saved state.
+	floor.add("2013-01-03T12:15:00Z", "fourth")
+	ctx, cancel = context.WithCancel(context.Background())
+	w = &FloorWatcher{StateFile: state, fetch: floor.fetch}
+	updates = w.Watch(ctx, "house", 5*time.Millisecond)
+	receive(t, updates, "fourth")
+	select {
+	case u := <-updates:
+		t.Errorf("unexpected update %v", u)
+	case <-time.After(20 * time.Millisecond):
+	}
+	cancel()
+	for range updates {
+	}
+}
+
+func TestFloorWatcherSameTimestamp(t *testing.T) {
+	defer func(perPage int) { PerPage = perPage }(PerPage)
+	PerPage = 1
+	floor := &fakeFloor{}
+	floor.add("2013-01-03T12:00:00Z", "first")
+	state := filepath.Join(t.TempDir(), "floor.json")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &FloorWatcher{StateFile: state, fetch: floor.fetch}
+	updates := w.Watch(ctx, "house", 5*time.Millisecond)
+	receive(t, updates, "first")
+	floor.add("2013-01-03T12:00:00Z", "second")
+	receive(t, updates, "second")
+	cancel()
+	for range updates {
+	}
+
+	// A restarted watcher remembers both updates with the timestamp.
+	floor.add("2013-01-03T12:00:00Z", "third")
+	ctx, cancel = context.WithCancel(context.Background())
+	w = &FloorWatcher{StateFile: state, fetch: floor.fetch}
+	updates = w.Watch(ctx, "house", 5*time.Millisecond)
+	receive(t, updates, "third")
+	select {
+	case u := <-updates:
+		t.Errorf("unexpected update %v", u)
+	case <-time.After(20 * time.Millisecond):
+	}
+	cancel()
+	for range updates {
+	}
+}
+
+func TestFloorWatcherMissedPages(t *testing.T) {
+	defer func(perPage int) { PerPage = perPage }(PerPage)
+	PerPage = 1
+	floor := &fakeFloor{}
+	for i := 0; i <= maxWatchPages+1; i++ {
+		floor.add(fmt.Sprintf("2013-01-03T12:%02d:00Z", i), fmt.Sprint(i))
+	}
+	last := floorMark{Time: time.Date(2013, 1, 3, 12, 0, 0, 0, time.UTC)}
+	last = last.add(last.Time, floor.updates[len(floor.updates)-1].key())
+
+	w := &FloorWatcher{fetch: floor.fetch}
+	updates, err := w.poll(context.Background(), "house", last)
+	if err == nil || !strings.Contains(err.Error(), "2013-01-03T12:02:00Z") {
+		t.Errorf("got error %v, want missed updates before 12:02", err)
+	}
+	if len(updates) != maxWatchPages || updates[0].Update != "2" {
+		t.Errorf("got updates %v, want the %v from 12:02", updates, maxWatchPages)
+	}
+}
+
+func TestFloorMarkReadsOldState(t *testing.T) {
+	var state map[string]floorMark
+	if err := json.Unmarshal([]byte(`{"house": "2013-01-03T12:00:00Z"}`), &state); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2013, 1, 3, 12, 0, 0, 0, time.UTC); !state["house"].Time.Equal(want) {
+		t.Errorf("read %v, want %v", state["house"], want)
+	}
+}
+
+func TestFloorWatcherBadInterval(t *testing.T) {
+	var errs []error
+	w := &FloorWatcher{OnError: func(err error) { errs = append(errs, err) }, fetch: (&fakeFloor{}).fetch}
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if u, ok := <-w.Watch(context.Background(), "house", interval); ok {
+			t.Errorf("Watch every %v sent %v, want a closed channel", interval, u)
+		}
+	}
+	if len(errs) != 2 {
+		t.Errorf("got errors %v, want one per bad interval", errs)
+	}
+}
@@ -0,0 +1,198 @@
+package gosunlight
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrNoGeocodeMatch is returned by a Geocoder when an address could not be
+// matched to a single location.
+var ErrNoGeocodeMatch = errors.New("Address could not be geocoded")
+
+// GeocodeQuality describes how closely a geocoded location matches the
+// requested address.
+type GeocodeQuality int
+
+const (
+	// GeocodeNoMatch means the address could not be located.
+	GeocodeNoMatch GeocodeQuality = iota
+	// GeocodeTie means the address matched more than one location.
+	GeocodeTie
+	// GeocodeNonExact means the address was located after correcting or
+	// interpolating part of it.
+	GeocodeNonExact
+	// GeocodeExact means the address matched exactly.
+	GeocodeExact
+)
+
+// String implements fmt.Stringer for geocode qualities
+func (q GeocodeQuality) String() string {
+	switch q {
+	case GeocodeTie:
+		return "tie"
+	case GeocodeNonExact:
+		return "non-exact"
+	case GeocodeExact:
+		return "exact"
+	}
+	return "no match"
+}
+
+// GeocodeResult is the location found for an address.
+type GeocodeResult struct {
+	Address        string
+	MatchedAddress string
+	Latitude       float64
+	Longitude      float64
+	Quality        GeocodeQuality
+}
+
+// Geocoder translates street addresses into coordinates.  Geocode should
+// return ErrNoGeocodeMatch, along with a result describing the failed
+// match, when an address cannot be located.
+type Geocoder interface {
+	Geocode(address string) (*GeocodeResult, error)
+}
+
+// DistrictForAddress geocodes an address and returns the district that
+// contains it, along with the geocoded location.
+func DistrictForAddress(geocoder Geocoder, address string) (*District, *GeocodeResult, error) {
+	result, err := geocoder.Geocode(address)
+	if err != nil {
+		return nil, result, err
+	}
+	district, err := DistrictFromLatLong(result.Latitude, result.Longitude)
+	return district, result, err
+}
+
+// LegislatorsForAddress geocodes an address and returns its legislators,
+// along with the geocoded location.  This is usually one Representative
+// and two Senators.
+func LegislatorsForAddress(geocoder Geocoder, address string) ([]*Legislator, *GeocodeResult, error) {
+	result, err := geocoder.Geocode(address)
+	if err != nil {
+		return nil, result, err
+	}
+	legislators, err := LegislatorsForLatLong(result.Latitude, result.Longitude)
+	return legislators, result, err
+}
+
+// CensusBatchGeocoder is a Geocoder backed by the results file produced by
+// the Census Bureau's batch geocoder.  Addresses are looked up by the input
+// address column of the file, ignoring case, punctuation and spacing.
+//
+// See: https://geocoding.geo.census.gov/geocoder/
+type CensusBatchGeocoder struct {
+	results map[string]*GeocodeResult
+}
+
+// NewCensusBatchGeocoder reads a Census batch geocoder results file.
+func NewCensusBatchGeocoder(r io.Reader) (*CensusBatchGeocoder, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	g := &CensusBatchGeocoder{results: make(map[string]*GeocodeResult)}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		result, err := parseCensusRecord(record)
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("Census batch file line %v: %v", line, err)
+		}
+		g.results[normalizeAddress(result.Address)] = result
+	}
+	return g, nil
+}
+
+// LoadCensusBatchGeocoder reads a Census batch geocoder results file from
+// disk.
+func LoadCensusBatchGeocoder(path string) (*CensusBatchGeocoder, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewCensusBatchGeocoder(f)
+}
+
+// Geocode implements Geocoder.
+func (g *CensusBatchGeocoder) Geocode(address string) (*GeocodeResult, error) {
+	result, ok := g.results[normalizeAddress(address)]
+	if !ok {
+		return &GeocodeResult{Address: address}, ErrNoGeocodeMatch
+	}
+	if result.Quality < GeocodeNonExact {
+		return result, ErrNoGeocodeMatch
+	}
+	return result, nil
+}
+
+// parseCensusRecord parses one line of a batch results file.  Matched lines
+// have the columns: id, input address, "Match", "Exact" or "Non_Exact",
+// matched address, "longitude,latitude", TIGER line id and side.  Unmatched
+// lines stop after the third column.
+func parseCensusRecord(record []string) (*GeocodeResult, error) {
+	if len(record) < 3 {
+		return nil, errors.New("too few columns")
+	}
+	result := &GeocodeResult{Address: record[1]}
+	switch record[2] {
+	case "No_Match":
+		return result, nil
+	case "Tie":
+		result.Quality = GeocodeTie
+		return result, nil
+	case "Match":
+	default:
+		return nil, fmt.Errorf("unknown match indicator %q", record[2])
+	}
+
+	if len(record) < 6 {
+		return nil, errors.New("too few columns for a match")
+	}
+	switch record[3] {
+	case "Exact":
+		result.Quality = GeocodeExact
+	case "Non_Exact":
+		result.Quality = GeocodeNonExact
+	default:
+		return nil, fmt.Errorf("unknown match type %q", record[3])
+	}
+	result.MatchedAddress = record[4]
+
+	coordinates := strings.Split(record[5], ",")
+	if len(coordinates) != 2 {
+		return nil, fmt.Errorf("bad coordinates %q", record[5])
+	}
+	var err error
+	if result.Longitude, err = strconv.ParseFloat(strings.TrimSpace(coordinates[0]), 64); err != nil {
+		return nil, fmt.Errorf("bad coordinates %q", record[5])
+	}
+	if result.Latitude, err = strconv.ParseFloat(strings.TrimSpace(coordinates[1]), 64); err != nil {
+		return nil, fmt.Errorf("bad coordinates %q", record[5])
+	}
+	return result, nil
+}
+
+// normalizeAddress uppercases an address and strips punctuation and extra
+// spaces so that small formatting differences still match.
+func normalizeAddress(address string) string {
+	address = strings.Map(func(r rune) rune {
+		switch r {
+		case ',', '.', '#':
+			return ' '
+		}
+		return r
+	}, strings.ToUpper(address))
+	return strings.Join(strings.Fields(address), " ")
+}
@@ -0,0 +1,38 @@
+package gosunlight
+
+import (
+	"strings"
+	"testing"
+)
+
+const censusBatchResults = `"1","4600 Silver Hill Rd, Washington, DC, 20233","Match","Exact","4600 SILVER HILL RD, WASHINGTON, DC, 20233","-76.92744,38.845985","76355984","L"
+"2","1 Nowhere Ln, Springfield, ZZ, 00000","No_Match"
+"3","100 Main St, Anytown, NC, 27513","Match","Non_Exact","100 MAIN ST, CARY, NC, 27513","-78.787805,35.778788","123","R"
+"4","5 Elm St, Salem","Tie"
+`
+
+func TestCensusBatchGeocoder(t *testing.T) {
+	g, err := NewCensusBatchGeocoder(strings.NewReader(censusBatchResults))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := g.Geocode("4600 silver hill rd washington dc 20233")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Quality != GeocodeExact || result.Latitude != 38.845985 || result.Longitude != -76.92744 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	result, err = g.Geocode("100 Main St., Anytown, NC 27513")
+	if err != nil || result.Quality != GeocodeNonExact {
+		t.Errorf("Geocode non-exact = %+v, %v", result, err)
+	}
+
+	for _, address := range []string{"1 Nowhere Ln, Springfield, ZZ, 00000", "5 Elm St, Salem", "not in file"} {
+		if _, err := g.Geocode(address); err != ErrNoGeocodeMatch {
+			t.Errorf("Geocode(%q) error = %v, want ErrNoGeocodeMatch", address, err)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package gosunlight
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// Point is a location in degrees of longitude and latitude.
+type Point struct {
+	Longitude float64
+	Latitude  float64
+}
+
+// Ring is a closed line of points.  The last point may repeat the first.
+type Ring []Point
+
+// Polygon is an outer ring followed by any holes cut out of it.
+type Polygon []Ring
+
+// MultiPolygon is a shape made of one or more polygons, such as a
+// district that includes islands.
+type MultiPolygon []Polygon
+
+// Bounds is a bounding box.
+type Bounds struct {
+	Min, Max Point
+}
+
+func emptyBounds() Bounds {
+	return Bounds{
+		Min: Point{math.Inf(1), math.Inf(1)},
+		Max: Point{math.Inf(-1), math.Inf(-1)},
+	}
+}
+
+func (b *Bounds) extend(p Point) {
+	b.Min.Longitude = math.Min(b.Min.Longitude, p.Longitude)
+	b.Min.Latitude = math.Min(b.Min.Latitude, p.Latitude)
+	b.Max.Longitude = math.Max(b.Max.Longitude, p.Longitude)
+	b.Max.Latitude = math.Max(b.Max.Latitude, p.Latitude)
+}
+
+// Intersects reports whether two bounding boxes overlap or touch.
+func (b Bounds) Intersects(o Bounds) bool {
+	return b.Min.Longitude <= o.Max.Longitude && o.Min.Longitude <= b.Max.Longitude &&
+		b.Min.Latitude <= o.Max.Latitude && o.Min.Latitude <= b.Max.Latitude
+}
+
+// Bounds returns the bounding box of a multipolygon.
+func (m MultiPolygon) Bounds() Bounds {
+	bounds := emptyBounds()
+	m.eachSegment(func(a, _ Point) { bounds.extend(a) })
+	return bounds
+}
+
+// Contains reports whether a point is inside the multipolygon and not in
+// one of its holes.
+func (m MultiPolygon) Contains(p Point) bool {
+	inside := false
+	m.eachSegment(func(a, b Point) {
+		if (a.Latitude > p.Latitude) != (b.Latitude > p.Latitude) &&
+			p.Longitude < a.Longitude+(p.Latitude-a.Latitude)*(b.Longitude-a.Longitude)/(b.Latitude-a.Latitude) {
+			inside = !inside
+		}
+	})
+	return inside
+}
+
+// eachSegment calls fn for each edge of every ring, closing rings that do
+// not repeat their first point.
+func (m MultiPolygon) eachSegment(fn func(a, b Point)) {
+	for _, polygon := range m {
+		for _, ring := range polygon {
+			for i := range ring {
+				j := i + 1
+				if j == len(ring) {
+					if ring[i] == ring[0] {
+						break
+					}
+					j = 0
+				}
+				fn(ring[i], ring[j])
+			}
+		}
+	}
+}
+
+// spans returns the longitudes at which a line of constant latitude enters
+// and leaves the multipolygon, as sorted pairs of [enter, leave].
+func (m MultiPolygon) spans(latitude float64) []float64 {
+	var crossings []float64
+	m.eachSegment(func(a, b Point) {
+		if (a.Latitude > latitude) != (b.Latitude > latitude) {
+			crossings = append(crossings, a.Longitude+(latitude-a.Latitude)*(b.Longitude-a.Longitude)/(b.Latitude-a.Latitude))
+		}
+	})
+	sort.Float64s(crossings)
+	return crossings
+}
+
+// spanLength returns the total length of a list of spans.
+func spanLength(spans []float64) float64 {
+	length := 0.0
+	for i := 0; i+1 < len(spans); i += 2 {
+		length += spans[i+1] - spans[i]
+	}
+	return length
+}
+
+// spanOverlap returns the length of the overlap between two lists of spans.
+func spanOverlap(a, b []float64) float64 {
+	overlap := 0.0
+	for i, j := 0, 0; i+1 < len(a) && j+1 < len(b); {
+		lo := math.Max(a[i], b[j])
+		hi := math.Min(a[i+1], b[j+1])
+		if hi > lo {
+			overlap += hi - lo
+		}
+		if a[i+1] < b[j+1] {
+			i += 2
+		} else {
+			j += 2
+		}
+	}
+	return overlap
+}
+
+// geoFeature is a single feature read from a GeoJSON file.
+type geoFeature struct {
+	Properties map[string]interface{}
+	Shape      MultiPolygon
+}
+
+// property returns the first of the named properties that is set, as a
+// string.
+func (f geoFeature) property(names ...string) string {
+	for _, name := range names {
+		if v, ok := f.Properties[name]; ok && v != nil {
+			return fmt.Sprintf("%v", v)
+		}
+	}
+	return ""
+}
+
+// readGeoJSON reads the polygon and multipolygon features from a GeoJSON
+// feature collection.  Features with other geometry types are skipped.
+func readGeoJSON(r io.Reader) ([]geoFeature, error) {
+	var collection struct {
+		Features []struct {
+			Properties map[string]interface{}
+			Geometry   *struct {
+				Type        string
+				Coordinates json.RawMessage
+			}
+		}
+	}
+	if err := json.NewDecoder(r).Decode(&collection); err != nil {
+		return nil, err
+	}
+
+	features := make([]geoFeature, 0, len(collection.Features))
+	for _, f := range collection.Features {
+		if f.Geometry == nil {
+			continue
+		}
+		var shape MultiPolygon
+		switch f.Geometry.Type {
+		case "Polygon":
+			var polygon [][][]float64
+			if err := json.Unmarshal(f.Geometry.Coordinates, &polygon); err != nil {
+				return nil, err
+			}
+			shape = MultiPolygon{toPolygon(polygon)}
+		case "MultiPolygon":
+			var polygons [][][][]float64
+			if err := json.Unmarshal(f.Geometry.Coordinates, &polygons); err != nil {
+				return nil, err
+			}
+			for _, polygon := range polygons {
+				shape = append(shape, toPolygon(polygon))
+			}
+		default:
+			continue
+		}
+		features = append(features, geoFeature{Properties: f.Properties, Shape: shape})
+	}
+	return features, nil
+}
+
+func toPolygon(coordinates [][][]float64) Polygon {
+	polygon := make(Polygon, 0, len(coordinates))
+	for _, ring := range coordinates {
+		r := make(Ring, 0, len(ring))
+		for _, position := range ring {
+			if len(position) >= 2 {
+				r = append(r, Point{Longitude: position[0], Latitude: position[1]})
+			}
+		}
+		polygon = append(polygon, r)
+	}
+	return polygon
+}
@@ -12,6 +12,7 @@
 package gosunlight
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -19,8 +20,11 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"time"
 )
 
+//go:generate go run ./internal/genparams -type Legislator,BillFilter,AmendmentFilter
+
 const (
 	sunlightURL = "http://services.sunlightlabs.com/api/"
 )
@@ -29,6 +33,10 @@ const (
 // will be pulled from the SUNLIGHT_KEY environment variable.
 var SunlightKey string
 
+// PerPage is the number of results requested in each page from endpoints
+// that paginate their results.
+var PerPage = 50
+
 // Pull the api key from the environment variables if it has not
 // been set in code.
 func init() {
@@ -38,7 +46,9 @@ func init() {
 	}
 }
 
-// An interface for types that can be translated to url parameters
+// An interface for types that can be translated to url parameters.  The
+// implementations for struct types are generated from their param or json
+// tags by internal/genparams.
 type paramable interface {
 	// adds the parameters in this type to a url.Values object
 	addTo(query *url.Values)
@@ -73,35 +83,87 @@ func newSunlightAPI(api, method string) sunlightAPI {
 // Runs the api request.  The JSON response is unmarshaled into
 // the v parameter
 func (api sunlightAPI) get(v interface{}, params ...paramable) error {
+	return api.getContext(context.Background(), v, params...)
+}
+
+// Runs the api request, canceling it if ctx is done before the response
+// is read.  The JSON response is unmarshaled into the v parameter
+func (api sunlightAPI) getContext(ctx context.Context, v interface{}, params ...paramable) error {
+	return api.stream(ctx, func(decoder *json.Decoder) error {
+		return decoder.Decode(&v)
+	}, params...)
+}
+
+// Runs the api request through the Middlewares, passing a decoder over
+// the response body to decode.  The body is closed once decode returns, so
+// decode may stop reading early.
+func (api sunlightAPI) stream(ctx context.Context, decode func(*json.Decoder) error, params ...paramable) error {
 
 	if SunlightKey == "" {
 		return errors.New("Sunlight API key not set")
 	}
 
-	fullURL, _ := url.Parse(api.rawURL)
-	query := fullURL.Query()
-	query.Add("apikey", SunlightKey)
+	query := url.Values{}
 	for _, p := range params {
 		p.addTo(&query)
 	}
-	fullURL.RawQuery = query.Encode()
+	req := &Request{
+		API:    api.api,
+		Method: api.method,
+		Params: query,
+		Header: make(http.Header),
+		ctx:    ctx,
+	}
 
-	res, err := http.Get(fullURL.String())
+	handler := Handler(api.send)
+	for i := len(Middlewares) - 1; i >= 0; i-- {
+		handler = Middlewares[i](handler)
+	}
+	res, err := handler(req)
 	if err != nil {
 		return err
 	}
+	if res == nil || res.Body == nil {
+		return errNoResponse
+	}
+	defer res.Body.Close()
 
 	if res.StatusCode == 400 {
 		errorMessage, _ := ioutil.ReadAll(res.Body)
 		return errors.New(string(errorMessage))
-	} else {
-		decoder := json.NewDecoder(res.Body)
-		defer res.Body.Close()
-		err = decoder.Decode(&v)
-		if err != nil {
-			return err
+	}
+	return decode(json.NewDecoder(res.Body))
+}
+
+// Sends a request to sunlight.  This is the innermost handler of the
+// middleware chain, and adds the API key to the request.
+func (api sunlightAPI) send(req *Request) (*Response, error) {
+	fullURL, _ := url.Parse(api.rawURL)
+	query := fullURL.Query()
+	for key, values := range req.Params {
+		for _, value := range values {
+			query.Add(key, value)
 		}
 	}
-	return nil
+	query.Set("apikey", SunlightKey)
+	fullURL.RawQuery = query.Encode()
 
+	httpReq, err := http.NewRequestWithContext(req.Context(), "GET", fullURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range req.Header {
+		httpReq.Header[key] = values
+	}
+	start := time.Now()
+	res, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+		Body:       res.Body,
+		Duration:   time.Since(start),
+	}, nil
 }
@@ -0,0 +1,356 @@
+package gosunlight
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// CommitteeGraph connects legislators who sit on the same committees.
+// Each legislator is a node, and two legislators are joined by an edge
+// weighted by the number of committees and subcommittees they share.
+// Legislators are identified by their Bioguide ID.
+type CommitteeGraph struct {
+	legislators map[string]*Legislator
+	edges       map[string]map[string]*GraphEdge
+}
+
+// GraphEdge joins two legislators who share committees.
+type GraphEdge struct {
+	A, B       *Legislator
+	Committees []*Committee
+}
+
+// Weight is the number of committees the legislators share.
+func (e *GraphEdge) Weight() int {
+	return len(e.Committees)
+}
+
+// CrossParty reports whether the edge joins legislators of different
+// parties.
+func (e *GraphEdge) CrossParty() bool {
+	return e.A.Party != e.B.Party
+}
+
+// NewCommitteeGraph builds the co-membership graph for committees whose
+// members have been loaded, for example with LoadRosters.  Every committee
+// and subcommittee in the trees counts as a shared seat.
+func NewCommitteeGraph(committees []*Committee) *CommitteeGraph {
+	g := &CommitteeGraph{
+		legislators: make(map[string]*Legislator),
+		edges:       make(map[string]map[string]*GraphEdge),
+	}
+	for _, c := range FlattenCommittees(committees) {
+		var members []*Legislator
+		seen := make(map[string]bool)
+		for _, l := range c.Members {
+			if l == nil || l.BioguideID == "" || seen[l.BioguideID] {
+				continue
+			}
+			seen[l.BioguideID] = true
+			if _, ok := g.legislators[l.BioguideID]; !ok {
+				g.legislators[l.BioguideID] = l
+			}
+			members = append(members, g.legislators[l.BioguideID])
+		}
+		for i, a := range members {
+			for _, b := range members[i+1:] {
+				g.edge(a, b).Committees = append(g.edge(a, b).Committees, c)
+			}
+		}
+	}
+	return g
+}
+
+// edge returns the edge between two legislators, creating it if needed.
+func (g *CommitteeGraph) edge(a, b *Legislator) *GraphEdge {
+	if a.BioguideID > b.BioguideID {
+		a, b = b, a
+	}
+	if g.edges[a.BioguideID] == nil {
+		g.edges[a.BioguideID] = make(map[string]*GraphEdge)
+	}
+	if g.edges[b.BioguideID] == nil {
+		g.edges[b.BioguideID] = make(map[string]*GraphEdge)
+	}
+	e, ok := g.edges[a.BioguideID][b.BioguideID]
+	if !ok {
+		e = &GraphEdge{A: a, B: b}
+		g.edges[a.BioguideID][b.BioguideID] = e
+		g.edges[b.BioguideID][a.BioguideID] = e
+	}
+	return e
+}
+
+// Legislators returns every legislator in the graph, ordered by Bioguide
+// ID.
+func (g *CommitteeGraph) Legislators() []*Legislator {
+	ids := g.ids()
+	legislators := make([]*Legislator, 0, len(ids))
+	for _, id := range ids {
+		legislators = append(legislators, g.legislators[id])
+	}
+	return legislators
+}
+
+func (g *CommitteeGraph) ids() []string {
+	ids := make([]string, 0, len(g.legislators))
+	for id := range g.legislators {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Edges returns every edge in the graph, ordered by the Bioguide IDs of
+// their legislators.
+func (g *CommitteeGraph) Edges() []*GraphEdge {
+	var edges []*GraphEdge
+	for _, a := range g.ids() {
+		for _, b := range g.neighborIDs(a) {
+			if a < b {
+				edges = append(edges, g.edges[a][b])
+			}
+		}
+	}
+	return edges
+}
+
+func (g *CommitteeGraph) neighborIDs(id string) []string {
+	ids := make([]string, 0, len(g.edges[id]))
+	for other := range g.edges[id] {
+		ids = append(ids, other)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Edge returns the edge between two legislators, or nil if they share no
+// committees.
+func (g *CommitteeGraph) Edge(a, b string) *GraphEdge {
+	return g.edges[a][b]
+}
+
+// Degree returns the number of legislators who share a committee with a
+// legislator.
+func (g *CommitteeGraph) Degree(id string) int {
+	return len(g.edges[id])
+}
+
+// WeightedDegree returns the total number of committee seats a legislator
+// shares with others.
+func (g *CommitteeGraph) WeightedDegree(id string) int {
+	total := 0
+	for _, e := range g.edges[id] {
+		total += e.Weight()
+	}
+	return total
+}
+
+// DegreeCentrality returns the share of other legislators that a
+// legislator shares a committee with, from 0 to 1.
+func (g *CommitteeGraph) DegreeCentrality(id string) float64 {
+	if len(g.legislators) < 2 {
+		return 0
+	}
+	return float64(g.Degree(id)) / float64(len(g.legislators)-1)
+}
+
+// BetweennessCentrality returns, for each legislator, the normalized share
+// of shortest paths between other legislators that pass through them.
+// Edges are treated as unweighted.
+func (g *CommitteeGraph) BetweennessCentrality() map[string]float64 {
+	ids := g.ids()
+	centrality := make(map[string]float64, len(ids))
+	for _, id := range ids {
+		centrality[id] = 0
+	}
+
+	// Brandes' algorithm: a breadth first search from each legislator,
+	// then accumulate dependencies in reverse order of distance.
+	for _, source := range ids {
+		var stack []string
+		predecessors := make(map[string][]string)
+		paths := map[string]float64{source: 1}
+		distance := map[string]int{source: 0}
+		queue := []string{source}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			stack = append(stack, v)
+			for _, w := range g.neighborIDs(v) {
+				if _, ok := distance[w]; !ok {
+					distance[w] = distance[v] + 1
+					queue = append(queue, w)
+				}
+				if distance[w] == distance[v]+1 {
+					paths[w] += paths[v]
+					predecessors[w] = append(predecessors[w], v)
+				}
+			}
+		}
+		dependency := make(map[string]float64)
+		for i := len(stack) - 1; i >= 0; i-- {
+			w := stack[i]
+			for _, v := range predecessors[w] {
+				dependency[v] += paths[v] / paths[w] * (1 + dependency[w])
+			}
+			if w != source {
+				centrality[w] += dependency[w]
+			}
+		}
+	}
+
+	// Each path was counted from both ends.
+	if n := float64(len(ids)); n > 2 {
+		for id := range centrality {
+			centrality[id] /= (n - 1) * (n - 2)
+		}
+	}
+	return centrality
+}
+
+// CrossPartyShare returns the share of a legislator's weighted degree
+// that is with members of other parties.
+func (g *CommitteeGraph) CrossPartyShare(id string) float64 {
+	total, cross := 0, 0
+	for _, e := range g.edges[id] {
+		total += e.Weight()
+		if e.CrossParty() {
+			cross += e.Weight()
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(cross) / float64(total)
+}
+
+// CrossPartyOverlap returns the share of all shared committee seats in the
+// graph that are between members of different parties.
+func (g *CommitteeGraph) CrossPartyOverlap() float64 {
+	total, cross := 0, 0
+	for _, e := range g.Edges() {
+		total += e.Weight()
+		if e.CrossParty() {
+			cross += e.Weight()
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(cross) / float64(total)
+}
+
+// committeeIDs lists the ids of the committees an edge represents.
+func (e *GraphEdge) committeeIDs() string {
+	ids := make([]string, 0, len(e.Committees))
+	for _, c := range e.Committees {
+		ids = append(ids, c.Id)
+	}
+	return strings.Join(ids, " ")
+}
+
+type graphML struct {
+	XMLName xml.Name `xml:"graphml"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	Keys    []graphMLKey
+	Graph   struct {
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+type graphMLKey struct {
+	XMLName xml.Name `xml:"key"`
+	ID      string   `xml:"id,attr"`
+	For     string   `xml:"for,attr"`
+	Name    string   `xml:"attr.name,attr"`
+	Type    string   `xml:"attr.type,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+// WriteGraphML writes the graph in GraphML format.  Nodes carry each
+// legislator's name, party and state; edges carry their weight and the
+// ids of the shared committees.
+func (g *CommitteeGraph) WriteGraphML(w io.Writer) error {
+	doc := graphML{XMLNS: "http://graphml.graphdrawing.org/xmlns"}
+	doc.Keys = []graphMLKey{
+		{ID: "name", For: "node", Name: "name", Type: "string"},
+		{ID: "party", For: "node", Name: "party", Type: "string"},
+		{ID: "state", For: "node", Name: "state", Type: "string"},
+		{ID: "weight", For: "edge", Name: "weight", Type: "int"},
+		{ID: "committees", For: "edge", Name: "committees", Type: "string"},
+	}
+	doc.Graph.EdgeDefault = "undirected"
+	for _, l := range g.Legislators() {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: l.BioguideID,
+			Data: []graphMLData{
+				{"name", l.FirstName + " " + l.LastName},
+				{"party", l.Party},
+				{"state", l.State},
+			},
+		})
+	}
+	for _, e := range g.Edges() {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: e.A.BioguideID,
+			Target: e.B.BioguideID,
+			Data: []graphMLData{
+				{"weight", fmt.Sprint(e.Weight())},
+				{"committees", e.committeeIDs()},
+			},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteDOT writes the graph in Graphviz DOT format.
+func (g *CommitteeGraph) WriteDOT(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, "graph committees {")
+	for _, l := range g.Legislators() {
+		fmt.Fprintf(b, "  %v [label=%v, party=%v, state=%v];\n",
+			dotQuote(l.BioguideID), dotQuote(l.FirstName+" "+l.LastName), dotQuote(l.Party), dotQuote(l.State))
+	}
+	for _, e := range g.Edges() {
+		fmt.Fprintf(b, "  %v -- %v [weight=%v, committees=%v];\n",
+			dotQuote(e.A.BioguideID), dotQuote(e.B.BioguideID), e.Weight(), dotQuote(e.committeeIDs()))
+	}
+	fmt.Fprintln(b, "}")
+	return b.Flush()
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
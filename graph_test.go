@@ -0,0 +1,70 @@
+package gosunlight
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func testCommitteeGraph() *CommitteeGraph {
+	a := &Legislator{BioguideID: "A000001", FirstName: "Ann", LastName: "Able", Party: "D"}
+	b := &Legislator{BioguideID: "B000002", FirstName: "Bob", LastName: "Baker", Party: "R"}
+	c := &Legislator{BioguideID: "C000003", FirstName: "Cal", LastName: "Cole", Party: "R"}
+	sub := &Committee{Id: "HSAG03", Members: []*Legislator{a, b}}
+	return NewCommitteeGraph([]*Committee{
+		{Id: "HSAG", Members: []*Legislator{a, b}, Subcommittees: []*Committee{sub}},
+		{Id: "HSAP", Members: []*Legislator{b, c}},
+	})
+}
+
+func TestCommitteeGraphMetrics(t *testing.T) {
+	g := testCommitteeGraph()
+	if len(g.Legislators()) != 3 || len(g.Edges()) != 2 {
+		t.Fatalf("got %v nodes and %v edges", len(g.Legislators()), len(g.Edges()))
+	}
+	if e := g.Edge("B000002", "A000001"); e == nil || e.Weight() != 2 || !e.CrossParty() {
+		t.Errorf("unexpected edge %+v", e)
+	}
+	if g.Edge("A000001", "C000003") != nil {
+		t.Error("A and C share no committees")
+	}
+	if g.Degree("B000002") != 2 || g.WeightedDegree("B000002") != 3 || g.DegreeCentrality("A000001") != .5 {
+		t.Error("unexpected degree metrics")
+	}
+	betweenness := g.BetweennessCentrality()
+	if betweenness["B000002"] != 1 || betweenness["A000001"] != 0 {
+		t.Errorf("unexpected betweenness %v", betweenness)
+	}
+	if share := g.CrossPartyShare("B000002"); share != 2.0/3 {
+		t.Errorf("CrossPartyShare(B) = %v, want 2/3", share)
+	}
+	if overlap := g.CrossPartyOverlap(); overlap != 2.0/3 {
+		t.Errorf("CrossPartyOverlap() = %v, want 2/3", overlap)
+	}
+}
+
+func TestCommitteeGraphExport(t *testing.T) {
+	g := testCommitteeGraph()
+
+	var dot bytes.Buffer
+	if err := g.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(dot.String(), `"A000001" -- "B000002" [weight=2, committees="HSAG HSAG03"];`) {
+		t.Errorf("unexpected DOT output:\n%v", dot.String())
+	}
+
+	var graphml bytes.Buffer
+	if err := g.WriteGraphML(&graphml); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<node id="C000003">`,
+		`<edge source="B000002" target="C000003">`,
+		`<data key="weight">1</data>`,
+	} {
+		if !strings.Contains(graphml.String(), want) {
+			t.Errorf("GraphML missing %q:\n%v", want, graphml.String())
+		}
+	}
+}
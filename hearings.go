@@ -0,0 +1,205 @@
+package gosunlight
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var hearingAPIS struct {
+	getList sunlightAPI
+}
+
+func init() {
+	hearingAPIS.getList = newSunlightAPI("hearings", "getList")
+}
+
+// Hearing represents a scheduled committee hearing from the sunlight api.
+type Hearing struct {
+	CommitteeID string   `json:"committee_id"`
+	Chamber     string   `json:"chamber"`
+	Congress    int      `json:"congress"`
+	OccursAt    string   `json:"occurs_at"`
+	Room        string   `json:"room"`
+	Description string   `json:"description"`
+	BillIDs     []string `json:"bill_ids"`
+	URL         string   `json:"url"`
+}
+
+// String implements fmt.Stringer for hearings
+func (h Hearing) String() string {
+	return fmt.Sprintf("%v %v %v", h.OccursAt, h.CommitteeID, h.Description)
+}
+
+// Time returns the time the hearing is scheduled to begin.
+func (h Hearing) Time() (time.Time, error) {
+	return time.Parse(time.RFC3339, h.OccursAt)
+}
+
+// uid identifies the hearing in iCalendar files.  It is derived from the
+// hearing's URL, or failing that its committee and bills, and not its
+// time, so a rescheduled hearing keeps its UID.  Hearings with neither are
+// known by their committee, description and day, as a committee may hold
+// several hearings described only as "Markup" or "Business Meeting".
+// These keep their UID only when rescheduled within the same day.
+func (h Hearing) uid() string {
+	key := h.URL
+	if key == "" && len(h.BillIDs) > 0 {
+		key = h.CommitteeID + "|" + strings.Join(h.BillIDs, ",")
+	}
+	if key == "" {
+		day, _, _ := strings.Cut(h.OccursAt, "T")
+		key = h.CommitteeID + "|" + h.Description + "|" + day
+	}
+	return fmt.Sprintf("%x@gosunlight", sha1.Sum([]byte(key)))
+}
+
+// HearingFilter limits the hearings returned by HearingGetList.  Fields
+// left empty are not used.
+type HearingFilter struct {
+	CommitteeID string
+	Chamber     string
+
+	// From and To limit hearings to those occurring in a range of times.
+	From time.Time
+	To   time.Time
+}
+
+// Implementation of paramable for hearing filters
+func (f HearingFilter) addTo(query *url.Values) {
+	if f.CommitteeID != "" {
+		query.Add("committee_id", f.CommitteeID)
+	}
+	if f.Chamber != "" {
+		query.Add("chamber", f.Chamber)
+	}
+	if !f.From.IsZero() {
+		query.Add("occurs_at__gte", f.From.UTC().Format(time.RFC3339))
+	}
+	if !f.To.IsZero() {
+		query.Add("occurs_at__lte", f.To.UTC().Format(time.RFC3339))
+	}
+}
+
+// HearingGetList returns the first page of hearings matching a filter.
+// Use HearingGetListIter for every page.
+//
+// See: http://services.sunlightlabs.com/docs/congressapi/hearings.getList/
+func HearingGetList(filter HearingFilter) ([]*Hearing, error) {
+	return collectList(context.Background(), hearingAPIS.getList, "hearings", (*hearingItem).hearing, filter)
+}
+
+// UpcomingHearings returns every hearing matching a filter that has not
+// yet begun, fetching each page from sunlight.  The filter's From time is
+// ignored.
+func UpcomingHearings(filter HearingFilter) ([]*Hearing, error) {
+	filter.From = time.Now()
+	return collect(HearingGetListIter(context.Background(), filter))
+}
+
+// Hearings returns every hearing this committee holds between from and
+// to, fetching each page from sunlight.  A zero time leaves that end of
+// the range open.
+func (c *Committee) Hearings(from, to time.Time) ([]*Hearing, error) {
+	return collect(HearingGetListIter(context.Background(), HearingFilter{CommitteeID: c.Id, From: from, To: to}))
+}
+
+// WriteICalendar writes hearings as an iCalendar (.ics) file, which most
+// calendar applications can import or subscribe to.  Each hearing becomes
+// an event with a stable UID, so a regenerated file updates events,
+// including those for rescheduled hearings, rather than duplicating them.
+//
+// See: http://tools.ietf.org/html/rfc5545
+func WriteICalendar(w io.Writer, name string, hearings []*Hearing) error {
+	b := bufio.NewWriter(w)
+	line := func(property, value string) {
+		writeICalendarLine(b, property+":"+value)
+	}
+
+	stamp := time.Now().UTC().Format(icalendarTime)
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//gosunlight//Congressional Hearings//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if name != "" {
+		line("X-WR-CALNAME", icalendarEscape(name))
+	}
+	for _, h := range hearings {
+		start, err := h.Time()
+		if err != nil {
+			return fmt.Errorf("Hearing %v has an invalid time: %v", h, err)
+		}
+		description := h.Description
+		if len(h.BillIDs) > 0 {
+			description += "\n\nBills: " + strings.Join(h.BillIDs, ", ")
+		}
+
+		line("BEGIN", "VEVENT")
+		line("UID", h.uid())
+		line("DTSTAMP", stamp)
+		line("DTSTART", start.UTC().Format(icalendarTime))
+		line("SUMMARY", icalendarEscape(h.CommitteeID+" hearing"))
+		line("DESCRIPTION", icalendarEscape(description))
+		if h.Room != "" {
+			line("LOCATION", icalendarEscape(h.Room))
+		}
+		if h.URL != "" {
+			line("URL", h.URL)
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return b.Flush()
+}
+
+const icalendarTime = "20060102T150405Z"
+
+// icalendarEscape escapes the characters with special meaning in iCalendar
+// text values.
+var icalendarEscape = strings.NewReplacer(
+	`\`, `\\`,
+	`;`, `\;`,
+	`,`, `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+).Replace
+
+// writeICalendarLine writes a content line, folding it onto continuation
+// lines so that no line is longer than 75 octets.  Lines are never folded
+// within a UTF-8 character.
+func writeICalendarLine(w *bufio.Writer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines begin with a space, which counts toward
+		// their length.
+		limit = 74
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+// hearingItem is an element of a list of hearings.
+type hearingItem struct {
+	Hearing *Hearing
+}
+
+func (i *hearingItem) hearing() *Hearing {
+	return i.Hearing
+}
+
+func (i *hearingItem) into(h *Hearing) {
+	i.Hearing = h
+}
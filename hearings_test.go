@@ -0,0 +1,90 @@
+package gosunlight
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteICalendar(t *testing.T) {
+	hearings := []*Hearing{{
+		CommitteeID: "SSAF",
+		OccursAt:    "2013-01-15T15:00:00-05:00",
+		Room:        "SR-328A, Russell Senate Office Building",
+		Description: "Hearings to examine the farm bill; the future of agriculture policy in the United States, and rural development programs.",
+		BillIDs:     []string{"s3240-112"},
+	}}
+	var out bytes.Buffer
+	if err := WriteICalendar(&out, "Agriculture", hearings); err != nil {
+		t.Fatal(err)
+	}
+	ics := out.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Agriculture\r\n",
+		"DTSTART:20130115T200000Z\r\n",
+		"LOCATION:SR-328A\\, Russell Senate Office Building\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("calendar missing %q:\n%v", want, ics)
+		}
+	}
+	for _, line := range strings.Split(ics, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+	}
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	if !strings.Contains(unfolded, `farm bill\; the future of agriculture policy in the United States\, and rural development programs.\n\nBills: s3240-112`) {
+		t.Errorf("description not escaped and folded correctly:\n%v", ics)
+	}
+}
+
+func TestHearingUID(t *testing.T) {
+	tests := []struct {
+		a, b Hearing
+		same bool
+	}{
+		{Hearing{URL: "http://example.senate.gov/h1", OccursAt: "2013-01-15T15:00:00Z"},
+			Hearing{URL: "http://example.senate.gov/h1", OccursAt: "2013-01-22T15:00:00Z"}, true},
+		{Hearing{CommitteeID: "SSAF", BillIDs: []string{"s3240-112"}, OccursAt: "2013-01-15T15:00:00Z"},
+			Hearing{CommitteeID: "SSAF", BillIDs: []string{"s3240-112"}, OccursAt: "2013-01-16T10:00:00Z", Room: "SD-106"}, true},
+		{Hearing{CommitteeID: "SSAF", Description: "Farm bill"}, Hearing{CommitteeID: "SSAF", Description: "Nominations"}, false},
+		{Hearing{URL: "http://example.senate.gov/h1"}, Hearing{URL: "http://example.senate.gov/h2"}, false},
+		{Hearing{CommitteeID: "HSAG", Description: "Markup", OccursAt: "2013-03-05T10:00:00-05:00"},
+			Hearing{CommitteeID: "HSAG", Description: "Markup", OccursAt: "2013-03-12T10:00:00-04:00"}, false},
+		{Hearing{CommitteeID: "HSAG", Description: "Markup", OccursAt: "2013-03-05T10:00:00-05:00"},
+			Hearing{CommitteeID: "HSAG", Description: "Markup", OccursAt: "2013-03-05T14:00:00-05:00", Room: "1300 LHOB"}, true},
+	}
+	for _, test := range tests {
+		if same := test.a.uid() == test.b.uid(); same != test.same {
+			t.Errorf("uid(%v) == uid(%v) is %v, want %v", test.a, test.b, same, test.same)
+		}
+	}
+}
+
+func TestCommitteeHearingsPages(t *testing.T) {
+	withPerPage(t, 2)
+	var pages []string
+	serve(t, func(req *Request) (string, error) {
+		page := req.Params.Get("page")
+		pages = append(pages, page)
+		switch page {
+		case "1":
+			return `{"response": {"hearings": [{"hearing": {"description": "a"}}, {"hearing": {"description": "b"}}]}}`, nil
+		case "2":
+			return `{"response": {"hearings": [{"hearing": {"description": "c"}}]}}`, nil
+		}
+		return `{"response": {"hearings": []}}`, nil
+	})
+	hearings, err := (&Committee{Id: "HSAG"}).Hearings(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hearings) != 3 || hearings[2].Description != "c" || len(pages) != 2 {
+		t.Errorf("got %v hearings from pages %v, want 3 from 2 pages", len(hearings), pages)
+	}
+}
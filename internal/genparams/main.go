@@ -0,0 +1,281 @@
+// Command genparams generates the query parameter encoders for gosunlight
+// types.  For each named struct type it writes an addTo method, which
+// implements the package's paramable interface, and a test of that
+// method.
+//
+// Each field is encoded under the name in its param tag, or failing that
+// its json tag, and only when it is not the zero value.  Fields tagged
+// param:"-" are skipped.  Fields without a name, with a malformed tag, or
+// of a type other than string, bool, int or float64 are errors, so that
+// no field is silently left out of queries.
+//
+// Usage:
+//
+//	go run ./internal/genparams -type Legislator,BillFilter
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma separated list of struct types to generate encoders for")
+	output    = flag.String("output", "params_gen.go", "file to write the encoders to")
+	testFile  = flag.String("test", "params_gen_test.go", "file to write the encoder tests to")
+	dir       = flag.String("dir", ".", "directory of the package containing the types")
+)
+
+// field is a struct field and the query parameter it is encoded as.
+type field struct {
+	name  string
+	kind  string
+	param string
+}
+
+// structType is a struct type to generate an encoder for.
+type structType struct {
+	name   string
+	fields []field
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("genparams: ")
+	flag.Parse()
+	if *typeNames == "" {
+		log.Fatal("-type is required")
+	}
+
+	pkg, decls, err := parsePackage(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var types []structType
+	for _, name := range strings.Split(*typeNames, ",") {
+		decl, ok := decls[name]
+		if !ok {
+			log.Fatalf("type %v is not a struct in %v", name, *dir)
+		}
+		t, err := newStructType(name, decl)
+		if err != nil {
+			log.Fatal(err)
+		}
+		types = append(types, t)
+	}
+
+	if err := write(filepath.Join(*dir, *output), generateEncoders(pkg, types)); err != nil {
+		log.Fatal(err)
+	}
+	if err := write(filepath.Join(*dir, *testFile), generateTests(pkg, types)); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parsePackage returns the package name and struct types declared in the
+// non-test files of a directory.
+func parsePackage(dir string) (string, map[string]*ast.StructType, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, err
+	}
+	fset := token.NewFileSet()
+	pkg := ""
+	decls := make(map[string]*ast.StructType)
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", nil, err
+		}
+		pkg = f.Name.Name
+		ast.Inspect(f, func(n ast.Node) bool {
+			if spec, ok := n.(*ast.TypeSpec); ok {
+				if s, ok := spec.Type.(*ast.StructType); ok {
+					decls[spec.Name.Name] = s
+				}
+			}
+			return true
+		})
+	}
+	return pkg, decls, nil
+}
+
+func newStructType(name string, decl *ast.StructType) (structType, error) {
+	t := structType{name: name}
+	for _, f := range decl.Fields.List {
+		tags := map[string]string{}
+		if f.Tag != nil {
+			raw, err := strconv.Unquote(f.Tag.Value)
+			if err == nil {
+				tags, err = parseTag(raw)
+			}
+			if err != nil {
+				return t, fmt.Errorf("%v: malformed struct tag %v: %v", name, f.Tag.Value, err)
+			}
+		}
+		param := tags["param"]
+		if param == "" {
+			param = strings.Split(tags["json"], ",")[0]
+		}
+		if param == "-" {
+			continue
+		}
+
+		kind := ""
+		if ident, ok := f.Type.(*ast.Ident); ok {
+			kind = ident.Name
+		}
+		for _, fieldName := range f.Names {
+			if !fieldName.IsExported() {
+				continue
+			}
+			if param == "" {
+				return t, fmt.Errorf("%v.%v has no param or json tag", name, fieldName.Name)
+			}
+			switch kind {
+			case "string", "bool", "int", "float64":
+			default:
+				return t, fmt.Errorf("%v.%v has unsupported type %v; tag it param:\"-\" and encode it by hand", name, fieldName.Name, kind)
+			}
+			t.fields = append(t.fields, field{name: fieldName.Name, kind: kind, param: param})
+		}
+	}
+	return t, nil
+}
+
+// parseTag parses a struct tag strictly, returning an error for anything
+// but space separated key:"value" pairs.
+func parseTag(tag string) (map[string]string, error) {
+	tags := make(map[string]string)
+	for tag != "" {
+		colon := strings.Index(tag, ":")
+		if colon <= 0 || colon+1 >= len(tag) || tag[colon+1] != '"' {
+			return nil, errors.New("expected key:\"value\"")
+		}
+		key := tag[:colon]
+		rest := tag[colon+1:]
+		end := 1
+		for end < len(rest) && rest[end] != '"' {
+			if rest[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(rest) {
+			return nil, errors.New("unterminated value")
+		}
+		value, err := strconv.Unquote(rest[:end+1])
+		if err != nil {
+			return nil, err
+		}
+		tags[key] = value
+		tag = rest[end+1:]
+		if tag != "" {
+			if tag[0] != ' ' {
+				return nil, fmt.Errorf("unexpected %q after %v", tag, key)
+			}
+			tag = strings.TrimLeft(tag, " ")
+		}
+	}
+	return tags, nil
+}
+
+const header = "// Code generated by genparams; DO NOT EDIT.\n\n"
+
+func generateEncoders(pkg string, types []structType) []byte {
+	var b bytes.Buffer
+	b.WriteString(header)
+	fmt.Fprintf(&b, "package %v\n\n", pkg)
+	imports := `"net/url"`
+	for _, t := range types {
+		for _, f := range t.fields {
+			if f.kind == "int" || f.kind == "float64" {
+				imports = `"net/url"` + "\n" + `"strconv"`
+			}
+		}
+	}
+	fmt.Fprintf(&b, "import (\n%v\n)\n", imports)
+	for _, t := range types {
+		fmt.Fprintf(&b, "\n// Implementation of paramable for %v\n", t.name)
+		fmt.Fprintf(&b, "func (v %v) addTo(query *url.Values) {\n", t.name)
+		for _, f := range t.fields {
+			switch f.kind {
+			case "string":
+				fmt.Fprintf(&b, "\tif v.%v != \"\" {\n\t\tquery.Add(%q, v.%v)\n\t}\n", f.name, f.param, f.name)
+			case "bool":
+				fmt.Fprintf(&b, "\tif v.%v {\n\t\tquery.Add(%q, \"true\")\n\t}\n", f.name, f.param)
+			case "int":
+				fmt.Fprintf(&b, "\tif v.%v != 0 {\n\t\tquery.Add(%q, strconv.Itoa(v.%v))\n\t}\n", f.name, f.param, f.name)
+			case "float64":
+				fmt.Fprintf(&b, "\tif v.%v != 0 {\n\t\tquery.Add(%q, strconv.FormatFloat(v.%v, 'f', -1, 64))\n\t}\n", f.name, f.param, f.name)
+			}
+		}
+		b.WriteString("}\n")
+	}
+	return b.Bytes()
+}
+
+func generateTests(pkg string, types []structType) []byte {
+	var b bytes.Buffer
+	b.WriteString(header)
+	fmt.Fprintf(&b, "package %v\n\n", pkg)
+	b.WriteString("import (\n\t\"net/url\"\n\t\"testing\"\n)\n")
+	for _, t := range types {
+		// Every field is set to a distinct value, so that the test fails
+		// if any field is left out or sent under the wrong name.
+		want := url.Values{}
+		var literal []string
+		for i, f := range t.fields {
+			switch f.kind {
+			case "string":
+				literal = append(literal, fmt.Sprintf("%v: %q", f.name, f.name))
+				want.Add(f.param, f.name)
+			case "bool":
+				literal = append(literal, fmt.Sprintf("%v: true", f.name))
+				want.Add(f.param, "true")
+			case "int":
+				literal = append(literal, fmt.Sprintf("%v: %v", f.name, i+1))
+				want.Add(f.param, strconv.Itoa(i+1))
+			case "float64":
+				literal = append(literal, fmt.Sprintf("%v: %v.5", f.name, i+1))
+				want.Add(f.param, fmt.Sprintf("%v.5", i+1))
+			}
+		}
+		fmt.Fprintf(&b, "\nfunc Test%vAddTo(t *testing.T) {\n", t.name)
+		b.WriteString("\tquery := url.Values{}\n")
+		fmt.Fprintf(&b, "\t%v{}.addTo(&query)\n", t.name)
+		b.WriteString("\tif len(query) != 0 {\n\t\tt.Errorf(\"zero value encoded as %v\", query.Encode())\n\t}\n\n")
+		fmt.Fprintf(&b, "\tv := %v{\n", t.name)
+		for _, l := range literal {
+			fmt.Fprintf(&b, "\t\t%v,\n", l)
+		}
+		b.WriteString("\t}\n")
+		b.WriteString("\tv.addTo(&query)\n")
+		fmt.Fprintf(&b, "\tif want := %q; query.Encode() != want {\n", want.Encode())
+		b.WriteString("\t\tt.Errorf(\"encoded as %v, want %v\", query.Encode(), want)\n\t}\n}\n")
+	}
+	return b.Bytes()
+}
+
+func write(path string, source []byte) error {
+	formatted, err := format.Source(source)
+	if err != nil {
+		return fmt.Errorf("formatting %v: %v", path, err)
+	}
+	return os.WriteFile(path, formatted, 0644)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want map[string]string
+	}{
+		{``, map[string]string{}},
+		{`json:"bioguide_id"`, map[string]string{"json": "bioguide_id"}},
+		{`json:"state,omitempty"  param:"state_abbr"`, map[string]string{"json": "state,omitempty", "param": "state_abbr"}},
+		{`param:"-"`, map[string]string{"param": "-"}},
+		{`json:"a\"b"`, map[string]string{"json": `a"b`}},
+	}
+	for _, test := range tests {
+		got, err := parseTag(test.tag)
+		if err != nil || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parseTag(%q) = %v, %v, want %v", test.tag, got, err, test.want)
+		}
+	}
+
+	for _, tag := range []string{
+		`json:"bioguide_id""`,
+		`json:"bioguide_id`,
+		`json:bioguide_id`,
+		`json`,
+		`:"bioguide_id"`,
+		`json:"a"param:"b"`,
+	} {
+		if got, err := parseTag(tag); err == nil {
+			t.Errorf("parseTag(%q) = %v, want error", tag, got)
+		}
+	}
+}
+
+// parseStruct parses the fields of a struct type.
+func parseStruct(t *testing.T, fields string) *ast.StructType {
+	t.Helper()
+	expr, err := parser.ParseExpr("struct {\n" + fields + "\n}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return expr.(*ast.StructType)
+}
+
+func TestNewStructType(t *testing.T) {
+	decl := parseStruct(t, "Name string `json:\"name\"`\n"+
+		"Votes int `json:\"votes\" param:\"vote_count\"`\n"+
+		"Score float64 `json:\"score,omitempty\"`\n"+
+		"Active bool `json:\"active\"`\n"+
+		"Parent *T `json:\"-\"`\n"+
+		"Hidden []string `param:\"-\"`\n"+
+		"private string")
+	got, err := newStructType("T", decl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := structType{name: "T", fields: []field{
+		{name: "Name", kind: "string", param: "name"},
+		{name: "Votes", kind: "int", param: "vote_count"},
+		{name: "Score", kind: "float64", param: "score"},
+		{name: "Active", kind: "bool", param: "active"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newStructType = %+v, want %+v", got, want)
+	}
+}
+
+func TestNewStructTypeErrors(t *testing.T) {
+	tests := []struct {
+		fields string
+		err    string
+	}{
+		{"BioguideID string `json:\"bioguide_id\"\"`", "malformed struct tag"},
+		{"BioguideID string `json:bioguide_id`", "malformed struct tag"},
+		{"BioguideID string", "no param or json tag"},
+		{"BioguideID string `xml:\"bioguide_id\"`", "no param or json tag"},
+		{"Committees []string `json:\"committees\"`", "unsupported type"},
+		{"Parent *T `json:\"parent\"`", "unsupported type"},
+		{"Count int64 `json:\"count\"`", "unsupported type"},
+	}
+	for _, test := range tests {
+		_, err := newStructType("T", parseStruct(t, test.fields))
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("newStructType(%q) returned error %v, want %q", test.fields, err, test.err)
+		}
+	}
+}
@@ -1,10 +1,10 @@
 package gosunlight
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
-	"reflect"
 )
 
 var legislatorApis struct {
@@ -53,7 +53,7 @@ type Legislator struct {
 	WebForm          string `json:"webform"`
 	Email            string `json:"email"`
 	CongressOffice   string `json:"congress_office"`
-	BioguideID       string `json:"bioguide_id""`
+	BioguideID       string `json:"bioguide_id"`
 	VoteSmartId      string `json:"votesmart_id"`
 	FECId            string `json:"fec_id"`
 	GovTrackId       string `json:"govtrack_id"`
@@ -64,8 +64,6 @@ type Legislator struct {
 	FaceBookID       string `json:"facebook_id"`
 	SenateClass      string `json:"senate_class"`
 	BirthDate        string `json:"birthdate"`
-
-	committees []*Committee
 }
 
 // String implements fmt.Stringer for legislators
@@ -112,13 +110,13 @@ func (l *Legislator) GetAll() error {
 	return legislatorApis.get.get(&r, l, p)
 }
 
-func getLegislator(allLegislators bool, legislator Legislator) (*Legislator, error) {
+func getLegislator(allLegislators bool, legislator Legislator, extra ...paramable) (*Legislator, error) {
 	var l legislatorResponse
 	p := params{}
 	if allLegislators {
 		p["all_legislators"] = 1
 	}
-	err := legislatorApis.get.get(&l, legislator, p)
+	err := legislatorApis.get.get(&l, append([]paramable{legislator, p}, extra...)...)
 	return l.Response.Legislator, err
 }
 
@@ -131,7 +129,7 @@ func getLegislator(allLegislators bool, legislator Legislator) (*Legislator, err
 //
 // See http://services.sunlightlabs.com/docs/congressapi/legislators.get(List)/
 func LegislatorGetList(legislators ...*Legislator) ([]*Legislator, error) {
-	return getLegislators(false, legislators...)
+	return getLegislators(false, legislators)
 }
 
 // LegislatorGetListAll all legislators which match the fields that are set in
@@ -143,20 +141,16 @@ func LegislatorGetList(legislators ...*Legislator) ([]*Legislator, error) {
 //
 // See http://services.sunlightlabs.com/docs/congressapi/legislators.get(List)/
 func LegislatorGetListAll(legislators ...*Legislator) ([]*Legislator, error) {
-	return getLegislators(true, legislators...)
+	return getLegislators(true, legislators)
 }
 
-func getLegislators(allLegislators bool, legislators ...*Legislator) ([]*Legislator, error) {
-	var r legislatorsResponse
+func getLegislators(allLegislators bool, legislators []*Legislator, extra ...paramable) ([]*Legislator, error) {
 	p := params{}
 	if allLegislators {
 		p["all_legislators"] = 1
 	}
-	err := legislatorApis.getList.get(&r, (legislatorSlice)(legislators), p)
-	if err != nil {
-		return nil, err
-	}
-	return r.slice(), nil
+	return collectList(context.Background(), legislatorApis.getList, "legislators", (*legislatorItem).legislator,
+		append([]paramable{(legislatorSlice)(legislators), p}, extra...)...)
 }
 
 // LegislatorSearch performs a fuzzy search on legislator name.  Each
@@ -185,18 +179,17 @@ func LegislatorSearchAll(name string) ([]*Legislator, error) {
 	return legislatorSearch(name, true)
 }
 
-func legislatorSearch(name string, allLegislators bool) ([]*Legislator, error) {
-	var r legislatorSearchResponse
-	p := params{
+func legislatorSearch(name string, allLegislators bool, extra ...paramable) ([]*Legislator, error) {
+	return collectList(context.Background(), legislatorApis.search, "results", (*legislatorSearchItem).legislator,
+		append([]paramable{legislatorSearchParams(name, allLegislators)}, extra...)...)
+}
+
+func legislatorSearchParams(name string, allLegislators bool) params {
+	return params{
 		"name":            name,
 		"threshold":       fmt.Sprintf("%v", LegislatorSearchTheshold),
 		"all_legislators": fmt.Sprintf("%v", allLegislators),
 	}
-	err := legislatorApis.search.get(&r, p)
-	if err != nil {
-		return nil, err
-	}
-	return r.slice(), nil
 }
 
 // LegislatorsForZip returns all legislators for a 5 digit zip code.
@@ -208,13 +201,13 @@ func legislatorSearch(name string, allLegislators bool) ([]*Legislator, error) {
 //
 // See: http://services.sunlightlabs.com/docs/congressapi/legislators.allForZip/
 func LegislatorsForZip(zip string) ([]*Legislator, error) {
-	var r legislatorsResponse
+	return legislatorsForZip(zip)
+}
+
+func legislatorsForZip(zip string, extra ...paramable) ([]*Legislator, error) {
 	p := params{"zip": zip}
-	err := legislatorApis.zip.get(&r, p)
-	if err != nil {
-		return nil, err
-	}
-	return r.slice(), nil
+	return collectList(context.Background(), legislatorApis.zip, "legislators", (*legislatorItem).legislator,
+		append([]paramable{p}, extra...)...)
 }
 
 // LegislatorsForLatLong returns all legislators for specific latitude and
@@ -224,34 +217,73 @@ func LegislatorsForZip(zip string) ([]*Legislator, error) {
 //
 // See: http://services.sunlightlabs.com/docs/congressapi/legislators.allForLatLong/
 func LegislatorsForLatLong(latitude, longitude float64) ([]*Legislator, error) {
-	var r legislatorsResponse
+	return legislatorsForLatLong(latitude, longitude)
+}
+
+func legislatorsForLatLong(latitude, longitude float64, extra ...paramable) ([]*Legislator, error) {
 	p := params{
 		"latitude":  fmt.Sprintf("%v", latitude),
 		"longitude": fmt.Sprintf("%v", longitude),
 	}
-	err := legislatorApis.latlon.get(&r, p)
-	if err != nil {
-		return nil, err
-	}
-	return r.slice(), nil
+	return collectList(context.Background(), legislatorApis.latlon, "legislators", (*legislatorItem).legislator,
+		append([]paramable{p}, extra...)...)
 }
 
 // Committees gets a list of the committees and subcommittees that this
 // legislator is a part of.  This is a wrapper around CommitteesForLegislator.
 // The first call to Committees will block while the committees are fetched
-// from sunlight.  Subsequent calls return a cached list.
-func (l Legislator) Committees() ([]*Committee, error) {
-	if l.committees == nil {
-		if l.BioguideID == "" {
-			return nil, errors.New("BioguideId missing for legislator.")
+// from sunlight.  Subsequent calls, for this or any other copy of the
+// legislator, return a cached list until it expires or is invalidated.
+// Concurrent first calls share a single request.
+func (l *Legislator) Committees() ([]*Committee, error) {
+	if l.BioguideID == "" {
+		return nil, errors.New("BioguideId missing for legislator.")
+	}
+	return legislatorCommittees.get(l.BioguideID, func() ([]*Committee, error) {
+		return CommitteesForLegislator(l.BioguideID)
+	})
+}
+
+// Invalidate discards the relations cached for this legislator, so that
+// they are fetched again on next use.
+func (l *Legislator) Invalidate() {
+	legislatorCommittees.forget(l.BioguideID)
+	legislatorsByBioguideID.forget(l.BioguideID)
+	legislatorAmendments.forget(l.BioguideID)
+	legislatorVotes.forgetWhere(func(f VoteFilter) bool {
+		return f.VoterID == l.BioguideID
+	})
+}
+
+// Leadership returns the committees and subcommittees on which this
+// legislator is a chair, vice chair or ranking member.  The first call
+// fetches the members of each of the legislator's committees from
+// sunlight.  The rosters are cached, and shared with other legislators'
+// calls, until they expire.  The returned memberships belong to the
+// cached rosters, and should not be modified.
+func (l *Legislator) Leadership() ([]*Membership, error) {
+	committees, err := l.Committees()
+	if err != nil {
+		return nil, err
+	}
+	var leadership []*Membership
+	for _, c := range FlattenCommittees(committees) {
+		if c.Id == "" {
+			return nil, fmt.Errorf("Cannot get members of committee %q: missing id", c.Name)
 		}
-		committees, err := CommitteesForLegislator(l.BioguideID)
+		roster, err := committeeRosters.get(c.Id, func() (*Committee, error) {
+			return committeeGet(context.Background(), c.Id)
+		})
 		if err != nil {
 			return nil, err
 		}
-		l.committees = committees
+		for _, m := range roster.Memberships {
+			if m.Legislator != nil && m.Legislator.BioguideID == l.BioguideID && m.Leadership() {
+				leadership = append(leadership, m)
+			}
+		}
 	}
-	return l.committees, nil
+	return leadership, nil
 }
 
 // Various types used to unmarshal JSON from sunlight.  These
@@ -263,39 +295,33 @@ type legislatorResponse struct {
 	}
 }
 
-type legislatorsResponse struct {
-	Response struct {
-		Legislators []struct {
-			Legislator *Legislator
-		}
-	}
+// legislatorItem is an element of a list of legislators.
+type legislatorItem struct {
+	Legislator *Legislator
 }
 
-func (lr legislatorsResponse) slice() []*Legislator {
-	results := make([]*Legislator, 0, len(lr.Response.Legislators))
-	for _, l := range lr.Response.Legislators {
-		results = append(results, l.Legislator)
-	}
-	return results
+func (i *legislatorItem) legislator() *Legislator {
+	return i.Legislator
 }
 
-type legislatorSearchResponse struct {
-	Response struct {
-		Results []struct {
-			Result struct {
-				Score      float64
-				Legislator *Legislator
-			}
-		}
-	}
+func (i *legislatorItem) into(l *Legislator) {
+	i.Legislator = l
 }
 
-func (lsr legislatorSearchResponse) slice() []*Legislator {
-	results := make([]*Legislator, 0, len(lsr.Response.Results))
-	for _, l := range lsr.Response.Results {
-		results = append(results, l.Result.Legislator)
+// legislatorSearchItem is an element of a list of search results.
+type legislatorSearchItem struct {
+	Result struct {
+		Score      float64
+		Legislator *Legislator
 	}
-	return results
+}
+
+func (i *legislatorSearchItem) legislator() *Legislator {
+	return i.Result.Legislator
+}
+
+func (i *legislatorSearchItem) into(l *Legislator) {
+	i.Result.Legislator = l
 }
 
 //Implementing paramable for a slice of legislators
@@ -306,20 +332,3 @@ func (ls legislatorSlice) addTo(query *url.Values) {
 		l.addTo(query)
 	}
 }
-
-// Implementation of paramable for legislators
-func (l Legislator) addTo(query *url.Values) {
-	typeOfLegislator := reflect.TypeOf(l)
-	valueOfLegislator := reflect.ValueOf(l)
-
-	for i := 0; i < typeOfLegislator.NumField(); i++ {
-		jsonKey := typeOfLegislator.Field(i).Tag.Get("json")
-		field := valueOfLegislator.Field(i)
-		if field.Kind() == reflect.String {
-			jsonValue, _ := field.Interface().(string)
-			if jsonValue != "" {
-				query.Add(jsonKey, jsonValue)
-			}
-		}
-	}
-}
@@ -0,0 +1,21 @@
+package gosunlight
+
+import (
+	"net/url"
+	"testing"
+)
+
+// TestLegislatorParams checks the query for a typical lookup against the
+// parameter names in the sunlight documentation, independently of the
+// tags the encoder is generated from.
+func TestLegislatorParams(t *testing.T) {
+	query := url.Values{}
+	legislatorSlice{
+		{Title: "Rep", LastName: "Pelosi", State: "CA", District: "12", InOffice: true},
+		{BioguideID: "P000197", FECId: "H8CA05035", TwitterID: "NancyPelosi", SenateClass: "I"},
+	}.addTo(&query)
+	want := "bioguide_id=P000197&district=12&fec_id=H8CA05035&in_office=true&lastname=Pelosi&senate_class=I&state=CA&title=Rep&twitter_id=NancyPelosi"
+	if query.Encode() != want {
+		t.Errorf("encoded as %v, want %v", query.Encode(), want)
+	}
+}
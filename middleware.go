@@ -0,0 +1,128 @@
+package gosunlight
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// Middlewares wrap every request to sunlight, in order, so the first
+// middleware sees each request first and its response last.  Set them
+// before making requests; they are not safe to change concurrently with
+// requests.
+var Middlewares []Middleware
+
+// Request is a call to a sunlight api, as seen by middleware.
+type Request struct {
+	// API and Method name the call, such as "legislators" and "getList".
+	API    string
+	Method string
+
+	// Params are the query parameters of the call.  The API key is added
+	// after every middleware has run, and is not included.
+	Params url.Values
+
+	// Header is sent with the HTTP request, for example to authenticate
+	// with a proxy or to propagate a trace.
+	Header http.Header
+
+	ctx context.Context
+}
+
+// Context returns the request's context.
+func (r *Request) Context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+// WithContext returns a copy of the request with its context replaced.
+// The copy has its own Params and Header, so changing them does not
+// change the original request.
+func (r *Request) WithContext(ctx context.Context) *Request {
+	clone := *r
+	clone.ctx = ctx
+	if r.Params != nil {
+		clone.Params = make(url.Values, len(r.Params))
+		for key, values := range r.Params {
+			clone.Params[key] = slices.Clone(values)
+		}
+	}
+	clone.Header = r.Header.Clone()
+	return &clone
+}
+
+// Response is the response to a Request.  Its body is read and closed by
+// gosunlight once every middleware has returned.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       io.ReadCloser
+
+	// Duration is the time until the response headers were received.
+	Duration time.Duration
+}
+
+// Handler sends a request and returns its response.
+type Handler func(req *Request) (*Response, error)
+
+// errNoResponse is returned for a request whose handler returned neither
+// a response nor an error.
+var errNoResponse = errors.New("Sunlight request returned no response")
+
+// Middleware wraps a Handler, to inspect or change requests and responses.
+// A middleware may also answer a request itself, without calling next.
+type Middleware func(next Handler) Handler
+
+// Logging returns middleware that logs each request with its parameters,
+// status and duration.  Successful requests are logged at Info level, and
+// failed ones at Error level.
+func Logging(logger *slog.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			start := time.Now()
+			res, err := next(req)
+			attrs := []slog.Attr{
+				slog.String("api", req.API),
+				slog.String("method", req.Method),
+				slog.String("params", req.Params.Encode()),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if err == nil && res == nil {
+				err = errNoResponse
+			}
+			if err != nil {
+				attrs = append(attrs, slog.Any("error", err))
+				logger.LogAttrs(req.Context(), slog.LevelError, "sunlight request failed", attrs...)
+				return res, err
+			}
+			attrs = append(attrs, slog.Int("status", res.StatusCode))
+			logger.LogAttrs(req.Context(), slog.LevelInfo, "sunlight request", attrs...)
+			return res, nil
+		}
+	}
+}
+
+// Timing returns middleware that reports the duration of each request to
+// record, along with its status, which is 0 if the request failed or had
+// no response.
+func Timing(record func(req *Request, status int, elapsed time.Duration)) Middleware {
+	return func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			start := time.Now()
+			res, err := next(req)
+			status := 0
+			if err == nil && res != nil {
+				status = res.StatusCode
+			}
+			record(req, status, time.Since(start))
+			return res, err
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package gosunlight
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withMiddlewares sets the middlewares and an API key for a test.
+func withMiddlewares(t *testing.T, m ...Middleware) {
+	oldMiddlewares, oldKey := Middlewares, SunlightKey
+	Middlewares, SunlightKey = m, "test-key"
+	t.Cleanup(func() { Middlewares, SunlightKey = oldMiddlewares, oldKey })
+}
+
+// answer is middleware that responds to every request itself.
+func answer(status int, body string, seen *[]*Request) Middleware {
+	return func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			*seen = append(*seen, req)
+			return &Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}, nil
+		}
+	}
+}
+
+// serve answers every request with the body returned by fn, in place of
+// sunlight.  An error from fn fails the request.
+func serve(t *testing.T, fn func(req *Request) (string, error)) {
+	withMiddlewares(t, func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			body, err := fn(req)
+			if err != nil {
+				return nil, err
+			}
+			return &Response{StatusCode: 200, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}, nil
+		}
+	})
+	t.Cleanup(ClearCache)
+}
+
+func TestMiddleware(t *testing.T) {
+	var logs bytes.Buffer
+	var timed []string
+	var seen []*Request
+	auth := func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			req.Header.Set("Proxy-Authorization", "Bearer token")
+			return next(req)
+		}
+	}
+	withMiddlewares(t,
+		Logging(slog.New(slog.NewTextHandler(&logs, nil))),
+		Timing(func(req *Request, status int, elapsed time.Duration) {
+			timed = append(timed, req.API+"."+req.Method)
+		}),
+		auth,
+		answer(200, `{"response": {"legislators": [{"legislator": {"firstname": "Nancy"}}]}}`, &seen),
+	)
+
+	legislators, err := LegislatorGetList(&Legislator{State: "CA"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(legislators) != 1 || legislators[0].FirstName != "Nancy" {
+		t.Errorf("got legislators %v", legislators)
+	}
+	if len(seen) != 1 || seen[0].Params.Get("state") != "CA" || seen[0].Header.Get("Proxy-Authorization") != "Bearer token" {
+		t.Errorf("unexpected request %+v", seen[0])
+	}
+	if seen[0].Params.Has("apikey") {
+		t.Error("API key exposed to middleware")
+	}
+	if len(timed) != 1 || timed[0] != "legislators.getList" {
+		t.Errorf("timed %v", timed)
+	}
+	for _, want := range []string{"api=legislators", "method=getList", `params="state=CA"`, "status=200"} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("log %q does not contain %q", logs.String(), want)
+		}
+	}
+}
+
+func TestMiddlewareBadRequest(t *testing.T) {
+	var seen []*Request
+	withMiddlewares(t, answer(400, "Invalid parameter", &seen))
+	if _, err := BillGet("hr1-113"); err == nil || err.Error() != "Invalid parameter" {
+		t.Errorf("got error %v, want the response body", err)
+	}
+}
+
+func TestMiddlewareNoResponse(t *testing.T) {
+	var logs bytes.Buffer
+	status := -1
+	withMiddlewares(t,
+		Logging(slog.New(slog.NewTextHandler(&logs, nil))),
+		Timing(func(req *Request, s int, elapsed time.Duration) { status = s }),
+		func(next Handler) Handler {
+			return func(req *Request) (*Response, error) { return nil, nil }
+		},
+	)
+	if _, err := BillGet("hr1-113"); err != errNoResponse {
+		t.Errorf("got error %v, want %v", err, errNoResponse)
+	}
+	if status != 0 || !strings.Contains(logs.String(), "sunlight request failed") {
+		t.Errorf("timed status %v, logged %q", status, logs.String())
+	}
+
+	Middlewares = Middlewares[2:]
+	if _, err := BillGet("hr1-113"); err != errNoResponse {
+		t.Errorf("without logging, got error %v, want %v", err, errNoResponse)
+	}
+}
+
+func TestRequestWithContext(t *testing.T) {
+	req := &Request{Params: url.Values{"state": {"CA"}}, Header: http.Header{"X-Trace": {"a"}}}
+	clone := req.WithContext(context.Background())
+	clone.Params.Set("state", "NY")
+	clone.Params["party"] = []string{"D"}
+	clone.Header.Set("X-Trace", "b")
+	if req.Params.Encode() != "state=CA" || req.Header.Get("X-Trace") != "a" {
+		t.Errorf("changing the clone changed the request to %v, %v", req.Params, req.Header)
+	}
+}
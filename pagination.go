@@ -0,0 +1,156 @@
+package gosunlight
+
+import (
+	"context"
+	"iter"
+	"reflect"
+)
+
+// pageParams returns the parameters requesting a page of PerPage results.
+// Pages are numbered from 1.
+func pageParams(page int) params {
+	return params{"page": page, "per_page": PerPage}
+}
+
+// paginate returns an iterator over the results of an endpoint, fetching
+// pages as they are needed.  fetch passes each result of a page to yield
+// as it is decoded, stopping if yield returns false, and returns the
+// number of results decoded.
+//
+// Iteration ends after the first page with fewer than PerPage results,
+// when the consumer stops early, or with the context's error when it is
+// done.  Endpoints that ignore pagination, such as the legislator and
+// committee lists, return every result on each page.  They are read in a
+// single page, which is detected when it has more than PerPage results or
+// when the next page starts with the same result.
+func paginate[T any](ctx context.Context, fetch func(ctx context.Context, page int, yield func(*T) bool) (int, error)) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		var previous *T
+		for page := 1; ; page++ {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			stopped, repeated := false, false
+			var first *T
+			n, err := fetch(ctx, page, func(result *T) bool {
+				if ctx.Err() != nil {
+					return false
+				}
+				if first == nil {
+					first = result
+					if previous != nil && reflect.DeepEqual(result, previous) {
+						repeated = true
+						return false
+					}
+				}
+				stopped = !yield(result, nil)
+				return !stopped
+			})
+			if stopped || repeated {
+				return
+			}
+			previous = first
+			if err == nil {
+				err = ctx.Err()
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if n != PerPage {
+				return
+			}
+		}
+	}
+}
+
+// collect returns every result of an iterator, or its first error.
+func collect[T any](results iter.Seq2[*T, error]) ([]*T, error) {
+	all := []*T{}
+	for result, err := range results {
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, result)
+	}
+	return all, nil
+}
+
+// LegislatorGetListIter is an iterator version of LegislatorGetList, which
+// fetches results a page at a time.
+func LegislatorGetListIter(ctx context.Context, legislators ...*Legislator) iter.Seq2[*Legislator, error] {
+	return paginate(ctx, streamPages(legislatorApis.getList, "legislators", (*legislatorItem).legislator,
+		(legislatorSlice)(legislators)))
+}
+
+// LegislatorGetListAllIter is an iterator version of LegislatorGetListAll,
+// which fetches results a page at a time.
+func LegislatorGetListAllIter(ctx context.Context, legislators ...*Legislator) iter.Seq2[*Legislator, error] {
+	return paginate(ctx, streamPages(legislatorApis.getList, "legislators", (*legislatorItem).legislator,
+		(legislatorSlice)(legislators), params{"all_legislators": 1}))
+}
+
+// LegislatorSearchIter is an iterator version of LegislatorSearch, which
+// fetches results a page at a time.
+func LegislatorSearchIter(ctx context.Context, name string) iter.Seq2[*Legislator, error] {
+	return paginate(ctx, streamPages(legislatorApis.search, "results", (*legislatorSearchItem).legislator,
+		legislatorSearchParams(name, false)))
+}
+
+// LegislatorSearchAllIter is an iterator version of LegislatorSearchAll,
+// which fetches results a page at a time.
+func LegislatorSearchAllIter(ctx context.Context, name string) iter.Seq2[*Legislator, error] {
+	return paginate(ctx, streamPages(legislatorApis.search, "results", (*legislatorSearchItem).legislator,
+		legislatorSearchParams(name, true)))
+}
+
+// CommitteeGetListIter is an iterator version of CommitteeGetList, which
+// fetches results a page at a time.
+func CommitteeGetListIter(ctx context.Context, chamber string) iter.Seq2[*Committee, error] {
+	return paginate(ctx, streamPages(committeeAPIS.getList, "committees", (*committeeItem).committee,
+		params{"chamber": chamber}))
+}
+
+// BillGetListIter is an iterator version of BillGetList, which fetches
+// results a page at a time.
+func BillGetListIter(ctx context.Context, filter BillFilter) iter.Seq2[*Bill, error] {
+	return paginate(ctx, streamPages(billAPIS.getList, "bills", (*billItem).bill, filter))
+}
+
+// BillSearchIter is an iterator version of BillSearch, which fetches
+// results a page at a time.
+func BillSearchIter(ctx context.Context, query string, filter BillFilter) iter.Seq2[*Bill, error] {
+	return paginate(ctx, streamPages(billAPIS.search, "bills", (*billItem).bill, params{"query": query}, filter))
+}
+
+// AmendmentGetListIter is an iterator version of AmendmentGetList, which
+// fetches results a page at a time.
+func AmendmentGetListIter(ctx context.Context, filter AmendmentFilter) iter.Seq2[*Amendment, error] {
+	return paginate(ctx, streamPages(amendmentAPIS.getList, "amendments", (*amendmentItem).amendment, filter))
+}
+
+// AmendmentSearchIter is an iterator version of AmendmentSearch, which
+// fetches results a page at a time.
+func AmendmentSearchIter(ctx context.Context, query string, filter AmendmentFilter) iter.Seq2[*Amendment, error] {
+	return paginate(ctx, streamPages(amendmentAPIS.search, "amendments", (*amendmentItem).amendment, params{"query": query}, filter))
+}
+
+// VoteGetListIter is an iterator version of VoteGetList, which fetches
+// results a page at a time.
+func VoteGetListIter(ctx context.Context, filter VoteFilter) iter.Seq2[*Vote, error] {
+	return paginate(ctx, streamPages(voteAPIS.getList, "votes", (*voteItem).vote, filter))
+}
+
+// HearingGetListIter is an iterator version of HearingGetList, which
+// fetches results a page at a time.
+func HearingGetListIter(ctx context.Context, filter HearingFilter) iter.Seq2[*Hearing, error] {
+	return paginate(ctx, streamPages(hearingAPIS.getList, "hearings", (*hearingItem).hearing, filter))
+}
+
+// FloorUpdateGetListIter iterates over the floor updates for a chamber,
+// newest first, fetching them a page at a time.
+func FloorUpdateGetListIter(ctx context.Context, chamber string) iter.Seq2[*FloorUpdate, error] {
+	return paginate(ctx, streamPages(floorAPIS.getList, "floor_updates", (*floorUpdateItem).floorUpdate,
+		params{"chamber": chamber, "order": "timestamp__desc"}))
+}
@@ -0,0 +1,123 @@
+package gosunlight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// testPages returns a page fetcher over n numbered results, recording the
+// pages requested.
+func testPages(n int, requested *[]int) func(ctx context.Context, page int, yield func(*int) bool) (int, error) {
+	return func(ctx context.Context, page int, yield func(*int) bool) (int, error) {
+		*requested = append(*requested, page)
+		count := 0
+		for i := (page - 1) * PerPage; i < page*PerPage && i < n; i++ {
+			count++
+			if !yield(&i) {
+				break
+			}
+		}
+		return count, nil
+	}
+}
+
+func withPerPage(t *testing.T, n int) {
+	old := PerPage
+	PerPage = n
+	t.Cleanup(func() { PerPage = old })
+}
+
+func TestPaginate(t *testing.T) {
+	withPerPage(t, 2)
+	var requested []int
+	count := 0
+	for _, err := range paginate(context.Background(), testPages(5, &requested)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		count++
+	}
+	if count != 5 || len(requested) != 3 {
+		t.Errorf("got %v results from %v pages, want 5 from 3", count, len(requested))
+	}
+}
+
+func TestPaginateStopsEarly(t *testing.T) {
+	withPerPage(t, 2)
+	var requested []int
+	for range paginate(context.Background(), testPages(10, &requested)) {
+		break
+	}
+	if len(requested) != 1 {
+		t.Errorf("fetched %v pages after stopping, want 1", len(requested))
+	}
+}
+
+func TestPaginateCanceled(t *testing.T) {
+	withPerPage(t, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var requested []int
+	var last error
+	count := 0
+	for _, err := range paginate(ctx, testPages(10, &requested)) {
+		if err != nil {
+			last = err
+			break
+		}
+		count++
+		if count == 3 {
+			cancel()
+		}
+	}
+	if !errors.Is(last, context.Canceled) || count != 3 || len(requested) != 2 {
+		t.Errorf("got %v after %v results from %v pages", last, count, len(requested))
+	}
+}
+
+func TestPaginateUnpaginatedEndpoint(t *testing.T) {
+	withPerPage(t, 2)
+	requests := 0
+	fetch := func(ctx context.Context, page int, yield func(*int) bool) (int, error) {
+		requests++
+		for i := 0; i < 3; i++ {
+			yield(&i)
+		}
+		return 3, nil
+	}
+	count := 0
+	for range paginate(context.Background(), fetch) {
+		count++
+	}
+	if count != 3 || requests != 1 {
+		t.Errorf("got %v results from %v requests, want 3 from 1", count, requests)
+	}
+}
+
+func TestPaginateRepeatedPage(t *testing.T) {
+	requests := 0
+	fetch := func(ctx context.Context, page int, yield func(*Legislator) bool) (int, error) {
+		requests++
+		for i := 0; i < PerPage; i++ {
+			if !yield(&Legislator{BioguideID: fmt.Sprintf("B%06d", i)}) {
+				return i + 1, nil
+			}
+		}
+		return PerPage, nil
+	}
+	seen := map[string]bool{}
+	for l, err := range paginate(context.Background(), fetch) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[l.BioguideID] {
+			t.Fatalf("%v yielded twice", l.BioguideID)
+		}
+		seen[l.BioguideID] = true
+	}
+	if len(seen) != PerPage || requests != 2 {
+		t.Errorf("got %v results from %v requests, want %v from 2", len(seen), requests, PerPage)
+	}
+}
@@ -0,0 +1,122 @@
+// Code generated by genparams; DO NOT EDIT.
+
+package gosunlight
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// Implementation of paramable for Legislator
+func (v Legislator) addTo(query *url.Values) {
+	if v.Title != "" {
+		query.Add("title", v.Title)
+	}
+	if v.FirstName != "" {
+		query.Add("firstname", v.FirstName)
+	}
+	if v.LastName != "" {
+		query.Add("lastname", v.LastName)
+	}
+	if v.NameSuffix != "" {
+		query.Add("name_suffix", v.NameSuffix)
+	}
+	if v.NickName != "" {
+		query.Add("nickname", v.NickName)
+	}
+	if v.Party != "" {
+		query.Add("party", v.Party)
+	}
+	if v.State != "" {
+		query.Add("state", v.State)
+	}
+	if v.District != "" {
+		query.Add("district", v.District)
+	}
+	if v.InOffice {
+		query.Add("in_office", "true")
+	}
+	if v.Gender != "" {
+		query.Add("gender", v.Gender)
+	}
+	if v.Phone != "" {
+		query.Add("phone", v.Phone)
+	}
+	if v.Fax != "" {
+		query.Add("fax", v.Fax)
+	}
+	if v.Website != "" {
+		query.Add("website", v.Website)
+	}
+	if v.WebForm != "" {
+		query.Add("webform", v.WebForm)
+	}
+	if v.Email != "" {
+		query.Add("email", v.Email)
+	}
+	if v.CongressOffice != "" {
+		query.Add("congress_office", v.CongressOffice)
+	}
+	if v.BioguideID != "" {
+		query.Add("bioguide_id", v.BioguideID)
+	}
+	if v.VoteSmartId != "" {
+		query.Add("votesmart_id", v.VoteSmartId)
+	}
+	if v.FECId != "" {
+		query.Add("fec_id", v.FECId)
+	}
+	if v.GovTrackId != "" {
+		query.Add("govtrack_id", v.GovTrackId)
+	}
+	if v.CRPID != "" {
+		query.Add("crp_id", v.CRPID)
+	}
+	if v.CongresspediaURL != "" {
+		query.Add("congresspedia_url", v.CongresspediaURL)
+	}
+	if v.TwitterID != "" {
+		query.Add("twitter_id", v.TwitterID)
+	}
+	if v.YouTubeURL != "" {
+		query.Add("youtube_url", v.YouTubeURL)
+	}
+	if v.FaceBookID != "" {
+		query.Add("facebook_id", v.FaceBookID)
+	}
+	if v.SenateClass != "" {
+		query.Add("senate_class", v.SenateClass)
+	}
+	if v.BirthDate != "" {
+		query.Add("birthdate", v.BirthDate)
+	}
+}
+
+// Implementation of paramable for BillFilter
+func (v BillFilter) addTo(query *url.Values) {
+	if v.SponsorID != "" {
+		query.Add("sponsor_id", v.SponsorID)
+	}
+	if v.CommitteeID != "" {
+		query.Add("committee_ids", v.CommitteeID)
+	}
+	if v.Congress != 0 {
+		query.Add("congress", strconv.Itoa(v.Congress))
+	}
+}
+
+// Implementation of paramable for AmendmentFilter
+func (v AmendmentFilter) addTo(query *url.Values) {
+	if v.SponsorID != "" {
+		query.Add("sponsor_id", v.SponsorID)
+	}
+	if v.AmendsBillID != "" {
+		query.Add("amends_bill_id", v.AmendsBillID)
+	}
+	if v.Chamber != "" {
+		query.Add("chamber", v.Chamber)
+	}
+	if v.Congress != 0 {
+		query.Add("congress", strconv.Itoa(v.Congress))
+	}
+}
@@ -0,0 +1,87 @@
+// Code generated by genparams; DO NOT EDIT.
+
+package gosunlight
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestLegislatorAddTo(t *testing.T) {
+	query := url.Values{}
+	Legislator{}.addTo(&query)
+	if len(query) != 0 {
+		t.Errorf("zero value encoded as %v", query.Encode())
+	}
+
+	v := Legislator{
+		Title:            "Title",
+		FirstName:        "FirstName",
+		LastName:         "LastName",
+		NameSuffix:       "NameSuffix",
+		NickName:         "NickName",
+		Party:            "Party",
+		State:            "State",
+		District:         "District",
+		InOffice:         true,
+		Gender:           "Gender",
+		Phone:            "Phone",
+		Fax:              "Fax",
+		Website:          "Website",
+		WebForm:          "WebForm",
+		Email:            "Email",
+		CongressOffice:   "CongressOffice",
+		BioguideID:       "BioguideID",
+		VoteSmartId:      "VoteSmartId",
+		FECId:            "FECId",
+		GovTrackId:       "GovTrackId",
+		CRPID:            "CRPID",
+		CongresspediaURL: "CongresspediaURL",
+		TwitterID:        "TwitterID",
+		YouTubeURL:       "YouTubeURL",
+		FaceBookID:       "FaceBookID",
+		SenateClass:      "SenateClass",
+		BirthDate:        "BirthDate",
+	}
+	v.addTo(&query)
+	if want := "bioguide_id=BioguideID&birthdate=BirthDate&congress_office=CongressOffice&congresspedia_url=CongresspediaURL&crp_id=CRPID&district=District&email=Email&facebook_id=FaceBookID&fax=Fax&fec_id=FECId&firstname=FirstName&gender=Gender&govtrack_id=GovTrackId&in_office=true&lastname=LastName&name_suffix=NameSuffix&nickname=NickName&party=Party&phone=Phone&senate_class=SenateClass&state=State&title=Title&twitter_id=TwitterID&votesmart_id=VoteSmartId&webform=WebForm&website=Website&youtube_url=YouTubeURL"; query.Encode() != want {
+		t.Errorf("encoded as %v, want %v", query.Encode(), want)
+	}
+}
+
+func TestBillFilterAddTo(t *testing.T) {
+	query := url.Values{}
+	BillFilter{}.addTo(&query)
+	if len(query) != 0 {
+		t.Errorf("zero value encoded as %v", query.Encode())
+	}
+
+	v := BillFilter{
+		SponsorID:   "SponsorID",
+		CommitteeID: "CommitteeID",
+		Congress:    3,
+	}
+	v.addTo(&query)
+	if want := "committee_ids=CommitteeID&congress=3&sponsor_id=SponsorID"; query.Encode() != want {
+		t.Errorf("encoded as %v, want %v", query.Encode(), want)
+	}
+}
+
+func TestAmendmentFilterAddTo(t *testing.T) {
+	query := url.Values{}
+	AmendmentFilter{}.addTo(&query)
+	if len(query) != 0 {
+		t.Errorf("zero value encoded as %v", query.Encode())
+	}
+
+	v := AmendmentFilter{
+		SponsorID:    "SponsorID",
+		AmendsBillID: "AmendsBillID",
+		Chamber:      "Chamber",
+		Congress:     4,
+	}
+	v.addTo(&query)
+	if want := "amends_bill_id=AmendsBillID&chamber=Chamber&congress=4&sponsor_id=SponsorID"; query.Encode() != want {
+		t.Errorf("encoded as %v, want %v", query.Encode(), want)
+	}
+}
@@ -0,0 +1,334 @@
+package gosunlight
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Speech is a single speech or statement from the Congressional Record.
+type Speech struct {
+	Date       time.Time
+	BioguideID string
+	Chamber    string
+	Speaker    string
+	// Party is the speaker's party.  If it is empty, the party is taken
+	// from the legislators added to the index.
+	Party string
+	Text  string
+}
+
+// Granularity is the length of the periods that phrase frequencies are
+// counted over.
+type Granularity int
+
+const (
+	ByDay Granularity = iota
+	ByMonth
+	ByYear
+)
+
+// truncate returns the start of the period containing t.
+func (g Granularity) truncate(t time.Time) time.Time {
+	switch g {
+	case ByMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case ByYear:
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// PeriodCount is the number of times a phrase was said in a period, and
+// the number of words spoken in that period.
+type PeriodCount struct {
+	Period time.Time
+	Count  int
+	Words  int
+}
+
+// PerMillion returns the phrase's frequency per million words spoken.
+func (p PeriodCount) PerMillion() float64 {
+	if p.Words == 0 {
+		return 0
+	}
+	return float64(p.Count) * 1e6 / float64(p.Words)
+}
+
+// LegislatorCount is the number of times a legislator said a phrase.
+type LegislatorCount struct {
+	BioguideID string
+	Count      int
+}
+
+// PhraseCount is the number of times a phrase was said.
+type PhraseCount struct {
+	Phrase string
+	Count  int
+}
+
+// RecordIndex is a full text index of Congressional Record speeches for
+// counting phrases, in the spirit of Sunlight's Capitol Words.  Text is
+// matched case insensitively, ignoring punctuation.
+//
+// See: http://capitolwords.org/api/1/
+type RecordIndex struct {
+	speeches []*Speech
+	tokens   [][]string
+
+	// postings lists the speeches each word appears in, in order.
+	postings map[string][]int
+	parties  map[string]string
+}
+
+// NewRecordIndex returns an empty index.
+func NewRecordIndex() *RecordIndex {
+	return &RecordIndex{
+		postings: make(map[string][]int),
+		parties:  make(map[string]string),
+	}
+}
+
+// Add indexes a speech.
+func (x *RecordIndex) Add(s *Speech) {
+	id := len(x.speeches)
+	tokens := tokenize(s.Text)
+	x.speeches = append(x.speeches, s)
+	x.tokens = append(x.tokens, tokens)
+	for _, token := range tokens {
+		list := x.postings[token]
+		if len(list) == 0 || list[len(list)-1] != id {
+			x.postings[token] = append(list, id)
+		}
+	}
+}
+
+// AddLegislators records the party of each legislator, for speeches that
+// do not include one.
+func (x *RecordIndex) AddLegislators(legislators []*Legislator) {
+	for _, l := range legislators {
+		x.parties[l.BioguideID] = l.Party
+	}
+}
+
+// Load indexes speeches from JSON lines, one speech per line, with the
+// fields "date" (YYYY-MM-DD), "bioguide_id", "chamber", "speaker",
+// "party" and "text".
+func (x *RecordIndex) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var doc struct {
+			Date       string `json:"date"`
+			BioguideID string `json:"bioguide_id"`
+			Chamber    string `json:"chamber"`
+			Speaker    string `json:"speaker"`
+			Party      string `json:"party"`
+			Text       string `json:"text"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &doc); err != nil {
+			return fmt.Errorf("Congressional Record line %v: %v", line, err)
+		}
+		date, err := time.Parse("2006-01-02", doc.Date)
+		if err != nil {
+			return fmt.Errorf("Congressional Record line %v: invalid date %q", line, doc.Date)
+		}
+		x.Add(&Speech{
+			Date:       date,
+			BioguideID: doc.BioguideID,
+			Chamber:    doc.Chamber,
+			Speaker:    doc.Speaker,
+			Party:      doc.Party,
+			Text:       doc.Text,
+		})
+	}
+	return scanner.Err()
+}
+
+// LoadFile indexes speeches from a JSON lines file on disk.
+func (x *RecordIndex) LoadFile(path string) error {
+	return loadFile(path, x.Load)
+}
+
+// party returns the party of a speech's speaker.
+func (x *RecordIndex) party(id int) string {
+	s := x.speeches[id]
+	if s.Party != "" {
+		return s.Party
+	}
+	return x.parties[s.BioguideID]
+}
+
+// matches calls fn with each speech containing a phrase and the number of
+// times it occurs there.
+func (x *RecordIndex) matches(phrase string, fn func(id, count int)) {
+	words := tokenize(phrase)
+	if len(words) == 0 {
+		return
+	}
+	// Only speeches containing the rarest word need to be searched.
+	candidates := x.postings[words[0]]
+	for _, word := range words[1:] {
+		if list := x.postings[word]; len(list) < len(candidates) {
+			candidates = list
+		}
+	}
+	for _, id := range candidates {
+		if count := countPhrase(x.tokens[id], words); count > 0 {
+			fn(id, count)
+		}
+	}
+}
+
+// Count returns the number of times a phrase was said between from and
+// to, inclusive.  A zero time leaves that end of the range open.
+func (x *RecordIndex) Count(phrase string, from, to time.Time) int {
+	total := 0
+	x.matches(phrase, func(id, count int) {
+		if inRange(x.speeches[id].Date, from, to) {
+			total += count
+		}
+	})
+	return total
+}
+
+// PhraseFrequency returns the number of times a phrase was said in each
+// period between from and to in which anything was said, in order.
+func (x *RecordIndex) PhraseFrequency(phrase string, granularity Granularity, from, to time.Time) []PeriodCount {
+	periods := make(map[time.Time]*PeriodCount)
+	for id, s := range x.speeches {
+		if !inRange(s.Date, from, to) {
+			continue
+		}
+		period := granularity.truncate(s.Date)
+		p, ok := periods[period]
+		if !ok {
+			p = &PeriodCount{Period: period}
+			periods[period] = p
+		}
+		p.Words += len(x.tokens[id])
+	}
+	x.matches(phrase, func(id, count int) {
+		if p, ok := periods[granularity.truncate(x.speeches[id].Date)]; ok {
+			p.Count += count
+		}
+	})
+
+	counts := make([]PeriodCount, 0, len(periods))
+	for _, p := range periods {
+		counts = append(counts, *p)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].Period.Before(counts[j].Period)
+	})
+	return counts
+}
+
+// LegislatorUsage returns the number of times each legislator said a
+// phrase between from and to, from most to least.
+func (x *RecordIndex) LegislatorUsage(phrase string, from, to time.Time) []LegislatorCount {
+	byLegislator := make(map[string]int)
+	x.matches(phrase, func(id, count int) {
+		s := x.speeches[id]
+		if s.BioguideID != "" && inRange(s.Date, from, to) {
+			byLegislator[s.BioguideID] += count
+		}
+	})
+	counts := make([]LegislatorCount, 0, len(byLegislator))
+	for id, count := range byLegislator {
+		counts = append(counts, LegislatorCount{BioguideID: id, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].BioguideID < counts[j].BioguideID
+	})
+	return counts
+}
+
+// TopPhrases returns the n phrases of a given number of words said most
+// often by members of a party between from and to.  Phrases that begin or
+// end with a common word such as "the" are skipped.
+func (x *RecordIndex) TopPhrases(party string, words, n int, from, to time.Time) []PhraseCount {
+	if words < 1 {
+		return nil
+	}
+	byPhrase := make(map[string]int)
+	for id, s := range x.speeches {
+		if x.party(id) != party || !inRange(s.Date, from, to) {
+			continue
+		}
+		tokens := x.tokens[id]
+		for i := 0; i+words <= len(tokens); i++ {
+			if recordStopWords[tokens[i]] || recordStopWords[tokens[i+words-1]] {
+				continue
+			}
+			byPhrase[strings.Join(tokens[i:i+words], " ")]++
+		}
+	}
+	counts := make([]PhraseCount, 0, len(byPhrase))
+	for phrase, count := range byPhrase {
+		counts = append(counts, PhraseCount{Phrase: phrase, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Phrase < counts[j].Phrase
+	})
+	if n >= 0 && len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
+
+// tokenize splits text into lower case words, dropping punctuation.
+// Apostrophes are removed rather than splitting words, so "nation's"
+// becomes "nations".
+func tokenize(text string) []string {
+	text = strings.NewReplacer("'", "", "’", "").Replace(text)
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// countPhrase returns the number of times words occur in order in tokens.
+func countPhrase(tokens, words []string) int {
+	count := 0
+outer:
+	for i := 0; i+len(words) <= len(tokens); i++ {
+		for j, word := range words {
+			if tokens[i+j] != word {
+				continue outer
+			}
+		}
+		count++
+	}
+	return count
+}
+
+func inRange(t, from, to time.Time) bool {
+	return (from.IsZero() || !t.Before(from)) && (to.IsZero() || !t.After(to))
+}
+
+// recordStopWords are common words, and words of floor procedure, that
+// make poor phrase boundaries.
+var recordStopWords = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`a an and are as at be but by for from has have
+		i in is it its of on or our that the their there these they this to was we
+		were will with would you your not no so if all my me he she his her them
+		mr madam speaker president gentleman gentlewoman yield time chair`) {
+		recordStopWords[word] = true
+	}
+}
@@ -0,0 +1,70 @@
+package gosunlight
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const recordJSON = `{"date": "2013-01-03", "bioguide_id": "A000001", "chamber": "house", "text": "Mr. Speaker, the fiscal cliff is here. The fiscal cliff!"}
+{"date": "2013-01-20", "bioguide_id": "B000002", "chamber": "house", "text": "We must avoid the Fiscal-Cliff and protect health care."}
+
+{"date": "2013-02-05", "bioguide_id": "A000001", "chamber": "house", "party": "R", "text": "Health care costs; health care reform."}
+`
+
+func testRecordIndex(t *testing.T) *RecordIndex {
+	x := NewRecordIndex()
+	if err := x.Load(strings.NewReader(recordJSON)); err != nil {
+		t.Fatal(err)
+	}
+	x.AddLegislators([]*Legislator{{BioguideID: "A000001", Party: "R"}, {BioguideID: "B000002", Party: "D"}})
+	return x
+}
+
+func TestRecordCount(t *testing.T) {
+	x := testRecordIndex(t)
+	if n := x.Count("fiscal cliff", time.Time{}, time.Time{}); n != 3 {
+		t.Errorf("Count(fiscal cliff) = %v, want 3", n)
+	}
+	from := time.Date(2013, 1, 10, 0, 0, 0, 0, time.UTC)
+	if n := x.Count("Fiscal Cliff", from, time.Time{}); n != 1 {
+		t.Errorf("Count(fiscal cliff) since Jan 10 = %v, want 1", n)
+	}
+	if n := x.Count("cliff fiscal", time.Time{}, time.Time{}); n != 0 {
+		t.Errorf("Count(cliff fiscal) = %v, want 0", n)
+	}
+}
+
+func TestPhraseFrequency(t *testing.T) {
+	x := testRecordIndex(t)
+	counts := x.PhraseFrequency("health care", ByMonth, time.Time{}, time.Time{})
+	if len(counts) != 2 {
+		t.Fatalf("got %v periods, want 2", len(counts))
+	}
+	if counts[0].Period.Month() != time.January || counts[0].Count != 1 || counts[0].Words != 20 {
+		t.Errorf("January = %+v", counts[0])
+	}
+	if counts[1].Count != 2 || counts[1].PerMillion() != 2e6/float64(counts[1].Words) {
+		t.Errorf("February = %+v", counts[1])
+	}
+}
+
+func TestLegislatorUsage(t *testing.T) {
+	x := testRecordIndex(t)
+	usage := x.LegislatorUsage("health care", time.Time{}, time.Time{})
+	if len(usage) != 2 || usage[0] != (LegislatorCount{"A000001", 2}) || usage[1] != (LegislatorCount{"B000002", 1}) {
+		t.Errorf("LegislatorUsage = %v", usage)
+	}
+}
+
+func TestTopPhrases(t *testing.T) {
+	x := testRecordIndex(t)
+	top := x.TopPhrases("R", 2, 2, time.Time{}, time.Time{})
+	if len(top) != 2 || top[0] != (PhraseCount{"fiscal cliff", 2}) || top[1] != (PhraseCount{"health care", 2}) {
+		t.Errorf("TopPhrases(R) = %v", top)
+	}
+	top = x.TopPhrases("D", 2, 1, time.Time{}, time.Time{})
+	if len(top) != 1 || top[0].Count != 1 {
+		t.Errorf("TopPhrases(D) = %v", top)
+	}
+}
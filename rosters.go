@@ -0,0 +1,208 @@
+package gosunlight
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RosterOptions controls how LoadRosters fetches committee members.
+type RosterOptions struct {
+	// Concurrency is the number of committees fetched at once.  Defaults
+	// to 4.
+	Concurrency int
+
+	// RequestsPerSecond limits how quickly requests are made to sunlight.
+	// Zero means no limit.
+	RequestsPerSecond float64
+
+	// SkipSubcommittees loads only the committees passed to LoadRosters,
+	// not their subcommittees.  The committees cached for each member are
+	// then left alone, as they would be missing every subcommittee.
+	SkipSubcommittees bool
+}
+
+// RosterError is returned by LoadRosters when some committees could not
+// be loaded.  The committees that did load are still populated.
+type RosterError struct {
+	// Failed maps each committee that failed to its error.  Committees are
+	// keyed by pointer, as committees missing an id fail too.
+	Failed map[*Committee]error
+}
+
+func (e *RosterError) Error() string {
+	messages := make([]string, 0, len(e.Failed))
+	for c, err := range e.Failed {
+		name := c.Id
+		if name == "" {
+			name = fmt.Sprintf("%q", c.Name)
+		}
+		messages = append(messages, fmt.Sprintf("%v: %v", name, err))
+	}
+	sort.Strings(messages)
+	return fmt.Sprintf("Failed to load %v committee rosters: %v", len(messages), strings.Join(messages, "; "))
+}
+
+// LoadRosters fetches the members of each committee, and each of their
+// subcommittees, in parallel.  It populates the Members and Memberships
+// fields of every committee.  A member of several committees is
+// represented by the same *Legislator in each.
+//
+// The committees loaded for each member are also merged into the
+// committees cached for them, so that Legislator.Committees does not need
+// to fetch them again.  A member's cached committees are only complete
+// once every committee tree they sit on is loaded, as when loading the
+// trees returned by CommitteeTree for each chamber.
+//
+// If some committees fail to load, a *RosterError listing them is
+// returned.  Committees not yet fetched when ctx is canceled fail with the
+// context's error.
+func LoadRosters(ctx context.Context, committees []*Committee, opts *RosterOptions) error {
+	if opts == nil {
+		opts = &RosterOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	all := committees
+	if !opts.SkipSubcommittees {
+		all = FlattenCommittees(committees)
+	}
+	seen := make(map[*Committee]bool)
+	var unique []*Committee
+	for _, c := range all {
+		if !seen[c] {
+			seen[c] = true
+			unique = append(unique, c)
+		}
+	}
+
+	var throttle <-chan time.Time
+	if opts.RequestsPerSecond > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.RequestsPerSecond))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	jobs := make(chan *Committee)
+	var lock sync.Mutex
+	failed := make(map[*Committee]error)
+	loaded := make(map[*Committee]*Committee)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range jobs {
+				roster, err := loadRoster(ctx, c, throttle)
+				lock.Lock()
+				if err != nil {
+					failed[c] = err
+				} else {
+					loaded[c] = roster
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+	for _, c := range unique {
+		jobs <- c
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Share one *Legislator per member across every committee, and record
+	// the committees each member sits on.
+	legislators := make(map[string]*Legislator)
+	memberOf := make(map[string][]*Committee)
+	for _, c := range unique {
+		roster, ok := loaded[c]
+		if !ok {
+			continue
+		}
+		c.Members = make([]*Legislator, 0, len(roster.Memberships))
+		c.Memberships = roster.Memberships
+		for _, m := range c.Memberships {
+			m.Committee = c
+			if m.Legislator == nil {
+				continue
+			}
+			if l, ok := legislators[m.Legislator.BioguideID]; ok && m.Legislator.BioguideID != "" {
+				m.Legislator = l
+			} else {
+				legislators[m.Legislator.BioguideID] = m.Legislator
+			}
+			c.Members = append(c.Members, m.Legislator)
+			if id := m.Legislator.BioguideID; id != "" {
+				memberOf[id] = append(memberOf[id], c)
+			}
+		}
+	}
+	if !opts.SkipSubcommittees {
+		for id, committees := range memberOf {
+			trees := memberTrees(committees)
+			legislatorCommittees.update(id, func(cached []*Committee, ok bool) []*Committee {
+				if !ok {
+					return trees
+				}
+				merged := slices.Clone(cached)
+				for _, c := range trees {
+					if FindCommittee(cached, c.Id) == nil {
+						merged = append(merged, c)
+					}
+				}
+				return merged
+			})
+		}
+	}
+
+	if len(failed) > 0 {
+		return &RosterError{Failed: failed}
+	}
+	return nil
+}
+
+// memberTrees arranges the committees a member sits on, parents before
+// subcommittees, into trees like those returned by
+// CommitteesForLegislator: copies of each committee whose Subcommittees
+// are only those the member also sits on.
+func memberTrees(committees []*Committee) []*Committee {
+	copies := make(map[*Committee]*Committee, len(committees))
+	var trees []*Committee
+	for _, c := range committees {
+		dup := *c
+		dup.Subcommittees = nil
+		copies[c] = &dup
+		if parent, ok := copies[c.Parent]; ok && c.Parent != nil {
+			dup.Parent = parent
+			parent.Subcommittees = append(parent.Subcommittees, &dup)
+		} else {
+			trees = append(trees, &dup)
+		}
+	}
+	return trees
+}
+
+// loadRoster fetches a single committee once the rate limit allows.
+func loadRoster(ctx context.Context, c *Committee, throttle <-chan time.Time) (*Committee, error) {
+	if throttle != nil {
+		select {
+		case <-throttle:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if c.Id == "" {
+		return nil, fmt.Errorf("Cannot get members of committee %q: missing id", c.Name)
+	}
+	return committeeGet(ctx, c.Id)
+}
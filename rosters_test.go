@@ -0,0 +1,182 @@
+package gosunlight
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// rosterServer serves committee lists, rosters and a legislator's
+// committees, failing requests for HSAP.
+func rosterServer(t *testing.T) {
+	var lock sync.Mutex
+	serve(t, func(req *Request) (string, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		switch req.Method + " " + req.Params.Encode() {
+		case "getList chamber=House":
+			return `{"response": {"committees": [
+				{"committee": {"id": "HSAG", "name": "Agriculture", "chamber": "House"}},
+				{"committee": {"id": "HSAP", "name": "Appropriations", "chamber": "House"}}
+			]}}`, nil
+		case "get id=HSAG":
+			return `{"response": {"committee": {"id": "HSAG", "members": [
+				{"legislator": {"bioguide_id": "L000491"}, "title": "Chairman"},
+				{"legislator": {"bioguide_id": "P000258"}, "title": "Ranking Member"}
+			]}}}`, nil
+		case "get id=HSAG15":
+			return `{"response": {"committee": {"id": "HSAG15", "members": [
+				{"legislator": {"bioguide_id": "L000491"}}
+			]}}}`, nil
+		case "allForLegislator bioguide_id=L000491":
+			return `{"response": {"committees": [
+				{"committee": {"id": "HSAG", "name": "Agriculture", "subcommittees": [
+					{"committee": {"id": "HSAG15", "name": "Livestock"}}
+				]}},
+				{"committee": {"id": "HSIF", "name": "Energy and Commerce"}}
+			]}}`, nil
+		case "get id=HSIF":
+			return `{"response": {"committee": {"id": "HSIF", "members": [
+				{"legislator": {"bioguide_id": "L000491"}, "title": "Vice Chair"}
+			]}}}`, nil
+		}
+		return "", errors.New("Unavailable")
+	})
+}
+
+func TestLoadRosters(t *testing.T) {
+	rosterServer(t)
+	ag := &Committee{Id: "HSAG", Subcommittees: []*Committee{{Id: "HSAG15"}}}
+	ap := &Committee{Id: "HSAP"}
+	unnamed := []*Committee{{Name: "First"}, {Name: "Second"}}
+	committees := append([]*Committee{ag, ap, ag}, unnamed...)
+
+	err := LoadRosters(context.Background(), committees, &RosterOptions{Concurrency: 2})
+	rosterErr, ok := err.(*RosterError)
+	if !ok {
+		t.Fatalf("got error %v, want a *RosterError", err)
+	}
+	if len(rosterErr.Failed) != 3 || rosterErr.Failed[ap] == nil || rosterErr.Failed[unnamed[0]] == nil || rosterErr.Failed[unnamed[1]] == nil {
+		t.Errorf("Failed = %v, want HSAP and both unnamed committees", rosterErr.Failed)
+	}
+	if !strings.Contains(err.Error(), `"First"`) || !strings.Contains(err.Error(), "HSAP") {
+		t.Errorf("unhelpful error %q", err)
+	}
+
+	livestock := ag.Subcommittees[0]
+	if len(ag.Members) != 2 || len(livestock.Members) != 1 {
+		t.Fatalf("got %v and %v members, want 2 and 1", len(ag.Members), len(livestock.Members))
+	}
+	if ag.Members[0] != livestock.Members[0] {
+		t.Error("member of two committees not shared")
+	}
+	if ag.Chair().Committee != ag {
+		t.Error("membership not linked to its committee")
+	}
+}
+
+func TestChairsKeepsCommittees(t *testing.T) {
+	rosterServer(t)
+	if _, err := Chairs("House"); err == nil {
+		t.Error("expected an error for HSAP")
+	}
+
+	committees, err := (&Legislator{BioguideID: "L000491"}).Committees()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(committees) != 2 || len(committees[0].Subcommittees) != 1 {
+		t.Errorf("Committees() = %v, want the full tree from sunlight", committees)
+	}
+}
+
+func TestLoadRostersCachesMemberCommittees(t *testing.T) {
+	var lock sync.Mutex
+	var requests []string
+	serve(t, func(req *Request) (string, error) {
+		lock.Lock()
+		requests = append(requests, req.Method+" "+req.Params.Encode())
+		lock.Unlock()
+		switch req.Params.Get("id") {
+		case "HSAG":
+			return `{"response": {"committee": {"id": "HSAG", "members": [
+				{"legislator": {"bioguide_id": "L000491"}, "title": "Chairman"},
+				{"legislator": {"bioguide_id": "P000258"}, "title": "Ranking Member"}
+			]}}}`, nil
+		case "HSAG15":
+			return `{"response": {"committee": {"id": "HSAG15", "members": [
+				{"legislator": {"bioguide_id": "L000491"}}
+			]}}}`, nil
+		}
+		return `{"response": {"committee": {"id": "` + req.Params.Get("id") + `", "members": []}}}`, nil
+	})
+	var response committeesResponse
+	if err := json.Unmarshal([]byte(committeesJSON), &response); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadRosters(context.Background(), response.committees(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	requests = nil
+	committees, err := (&Legislator{BioguideID: "L000491"}).Committees()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 0 {
+		t.Errorf("Committees() made requests %v", requests)
+	}
+	if len(committees) != 1 || committees[0].Id != "HSAG" || len(committees[0].Subcommittees) != 1 || committees[0].Subcommittees[0].Id != "HSAG15" {
+		t.Errorf("Committees() = %v, want HSAG and only its HSAG15 subcommittee", committees)
+	}
+	if committees[0].Chair() == nil || committees[0].Chair().Legislator.BioguideID != "L000491" {
+		t.Error("cached committees missing their rosters")
+	}
+	ranking, err := (&Legislator{BioguideID: "P000258"}).Committees()
+	if err != nil || len(ranking) != 1 || len(ranking[0].Subcommittees) != 0 {
+		t.Errorf("Committees() = %v, %v, want only HSAG", ranking, err)
+	}
+}
+
+func TestLegislatorLeadership(t *testing.T) {
+	var lock sync.Mutex
+	requests := make(map[string]int)
+	serve(t, func(req *Request) (string, error) {
+		lock.Lock()
+		requests[req.Method+" "+req.Params.Encode()]++
+		lock.Unlock()
+		if req.Method == "allForLegislator" {
+			return `{"response": {"committees": [
+				{"committee": {"id": "HSAG", "name": "Agriculture", "subcommittees": [
+					{"committee": {"id": "HSAG15", "name": "Livestock"}}
+				]}}
+			]}}`, nil
+		}
+		return `{"response": {"committee": {"id": "` + req.Params.Get("id") + `", "members": [
+			{"legislator": {"bioguide_id": "L000491"}, "title": "Chairman"},
+			{"legislator": {"bioguide_id": "P000258"}, "title": "Ranking Member"}
+		]}}}`, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			leadership, err := (&Legislator{BioguideID: "L000491"}).Leadership()
+			if err != nil || len(leadership) != 2 || leadership[0].Role != RoleChair {
+				t.Errorf("Leadership() = %v, %v, want chair of HSAG and HSAG15", leadership, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if _, err := (&Legislator{BioguideID: "L000491"}).Leadership(); err != nil {
+		t.Fatal(err)
+	}
+	if requests["get id=HSAG"] != 1 || requests["get id=HSAG15"] != 1 {
+		t.Errorf("rosters fetched %v times", requests)
+	}
+}
@@ -0,0 +1,429 @@
+package gosunlight
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// StateChamber is a chamber of a state legislature.
+type StateChamber string
+
+const (
+	// StateUpper is the state senate.
+	StateUpper StateChamber = "upper"
+	// StateLower is the state house or assembly.  Nebraska and DC have no
+	// lower chamber.
+	StateLower StateChamber = "lower"
+)
+
+// StateLegislator is a member of a state legislature, as published in
+// Open States bulk data.
+//
+// See: https://open.pluralpolicy.com/data/
+type StateLegislator struct {
+	// ID is the Open Civic Data person id, such as
+	// "ocd-person/6bbea6c2-...".
+	ID         string
+	Name       string
+	GivenName  string
+	FamilyName string
+	Party      string
+	Email      string
+	Image      string
+
+	// State is the two letter postal code of the legislator's state.
+	State    string
+	Chamber  StateChamber
+	District string
+
+	CapitolAddress  string
+	CapitolPhone    string
+	DistrictAddress string
+	DistrictPhone   string
+}
+
+// String implements fmt.Stringer for state legislators
+func (l StateLegislator) String() string {
+	return fmt.Sprintf("%v (%v) %v", l.Name, l.Party, l.StateDistrict())
+}
+
+// StateDistrict returns the district the legislator represents.
+func (l StateLegislator) StateDistrict() StateDistrict {
+	return StateDistrict{State: l.State, Chamber: l.Chamber, District: l.District}
+}
+
+// StateDistrict is a state legislative district.  Districts are named as
+// in Open States, which is usually a number without leading zeros, but in
+// some states is a name such as "Suffolk 3".
+type StateDistrict struct {
+	State    string
+	Chamber  StateChamber
+	District string
+}
+
+// String implements fmt.Stringer for state districts, such as
+// "NY upper 12".
+func (d StateDistrict) String() string {
+	return fmt.Sprintf("%v %v %v", d.State, d.Chamber, d.District)
+}
+
+// StateRepresentation is the state legislative districts containing a
+// location, and the legislators who represent them.  Districts in some
+// states elect several members, so each chamber may have more than one
+// legislator.
+type StateRepresentation struct {
+	// Lower is nil in states without a lower chamber.
+	Upper, Lower *StateDistrict
+
+	UpperLegislators []*StateLegislator
+	LowerLegislators []*StateLegislator
+}
+
+// Legislators returns every state legislator in the representation, upper
+// chamber first.
+func (r *StateRepresentation) Legislators() []*StateLegislator {
+	legislators := make([]*StateLegislator, 0, len(r.UpperLegislators)+len(r.LowerLegislators))
+	legislators = append(legislators, r.UpperLegislators...)
+	return append(legislators, r.LowerLegislators...)
+}
+
+// StateDirectory answers which state legislators represent a location.  It
+// is built from Open States bulk exports of legislators and Census Bureau
+// TIGER/Line state legislative district (SLDU and SLDL) boundaries
+// converted to GeoJSON.
+type StateDirectory struct {
+	legislators map[StateDistrict][]*StateLegislator
+	districts   map[StateDistrict]*stateDistrictShape
+}
+
+type stateDistrictShape struct {
+	district StateDistrict
+	shape    MultiPolygon
+	bounds   Bounds
+}
+
+// NewStateDirectory returns an empty state directory.
+func NewStateDirectory() *StateDirectory {
+	return &StateDirectory{
+		legislators: make(map[StateDistrict][]*StateLegislator),
+		districts:   make(map[StateDistrict]*stateDistrictShape),
+	}
+}
+
+// AddLegislator adds a legislator to the directory.
+func (s *StateDirectory) AddLegislator(l *StateLegislator) {
+	d := l.StateDistrict()
+	s.legislators[d] = append(s.legislators[d], l)
+}
+
+// AddDistrict adds the shape of a district, replacing any shape already
+// loaded for it.
+func (s *StateDirectory) AddDistrict(d StateDistrict, shape MultiPolygon) {
+	s.districts[d] = &stateDistrictShape{district: d, shape: shape, bounds: shape.Bounds()}
+}
+
+// Legislators returns the legislators who represent a district, ordered by
+// name.
+func (s *StateDirectory) Legislators(d StateDistrict) []*StateLegislator {
+	legislators := append([]*StateLegislator(nil), s.legislators[d]...)
+	sort.Slice(legislators, func(i, j int) bool {
+		return legislators[i].Name < legislators[j].Name
+	})
+	return legislators
+}
+
+// LoadOpenStatesCSV reads a state's legislators from an Open States bulk
+// CSV export.  The export does not name the state, so it must be given as
+// a two letter postal code.
+func (s *StateDirectory) LoadOpenStatesCSV(state string, r io.Reader) error {
+	if StateForAbbreviation(state) == nil {
+		return fmt.Errorf("Unknown state %q", state)
+	}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	for _, name := range []string{"id", "name", "current_chamber", "current_district"} {
+		if _, ok := columns[name]; !ok {
+			return fmt.Errorf("Open States CSV is missing the %v column", name)
+		}
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		chamber, err := parseStateChamber(field("current_chamber"))
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return fmt.Errorf("Open States CSV line %v: %v", line, err)
+		}
+		s.AddLegislator(&StateLegislator{
+			ID:              field("id"),
+			Name:            field("name"),
+			GivenName:       field("given_name"),
+			FamilyName:      field("family_name"),
+			Party:           field("current_party"),
+			Email:           field("email"),
+			Image:           field("image"),
+			State:           state,
+			Chamber:         chamber,
+			District:        field("current_district"),
+			CapitolAddress:  field("capitol_address"),
+			CapitolPhone:    field("capitol_voice"),
+			DistrictAddress: field("district_address"),
+			DistrictPhone:   field("district_voice"),
+		})
+	}
+}
+
+// openStatesPerson is a person in an Open States JSON export.
+type openStatesPerson struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	GivenName  string `json:"given_name"`
+	FamilyName string `json:"family_name"`
+	Email      string `json:"email"`
+	Image      string `json:"image"`
+	Party      []struct {
+		Name    string `json:"name"`
+		EndDate string `json:"end_date"`
+	} `json:"party"`
+	Roles []struct {
+		Type         string `json:"type"`
+		District     string `json:"district"`
+		Jurisdiction string `json:"jurisdiction"`
+		EndDate      string `json:"end_date"`
+	} `json:"roles"`
+	Offices []struct {
+		Classification string `json:"classification"`
+		Address        string `json:"address"`
+		Voice          string `json:"voice"`
+	} `json:"offices"`
+}
+
+// LoadOpenStatesJSON reads legislators from an Open States bulk JSON
+// export, which is an array of people.  Each person is added for their
+// current legislative role; people without one are skipped.
+func (s *StateDirectory) LoadOpenStatesJSON(r io.Reader) error {
+	var people []openStatesPerson
+	if err := json.NewDecoder(r).Decode(&people); err != nil {
+		return err
+	}
+	for _, p := range people {
+		l := &StateLegislator{
+			ID:         p.ID,
+			Name:       p.Name,
+			GivenName:  p.GivenName,
+			FamilyName: p.FamilyName,
+			Email:      p.Email,
+			Image:      p.Image,
+		}
+		for _, party := range p.Party {
+			if party.EndDate == "" {
+				l.Party = party.Name
+			}
+		}
+		for _, office := range p.Offices {
+			switch office.Classification {
+			case "capitol":
+				l.CapitolAddress, l.CapitolPhone = office.Address, office.Voice
+			case "district":
+				l.DistrictAddress, l.DistrictPhone = office.Address, office.Voice
+			}
+		}
+
+		current := false
+		for _, role := range p.Roles {
+			if role.EndDate != "" {
+				continue
+			}
+			chamber, err := parseStateChamber(role.Type)
+			if err != nil {
+				// Governors and other executive roles.
+				continue
+			}
+			state, err := jurisdictionState(role.Jurisdiction)
+			if err != nil {
+				return fmt.Errorf("Open States person %v: %v", p.ID, err)
+			}
+			l.State, l.Chamber, l.District = state, chamber, role.District
+			current = true
+		}
+		if current {
+			s.AddLegislator(l)
+		}
+	}
+	return nil
+}
+
+// LoadOpenStatesCSVFile reads a state's legislators from an Open States
+// bulk CSV export on disk.
+func (s *StateDirectory) LoadOpenStatesCSVFile(state, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return s.LoadOpenStatesCSV(state, f)
+}
+
+// LoadOpenStatesJSONFile reads legislators from an Open States bulk JSON
+// export on disk.
+func (s *StateDirectory) LoadOpenStatesJSONFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return s.LoadOpenStatesJSON(f)
+}
+
+// LoadDistricts reads state legislative district shapes from a GeoJSON
+// feature collection converted from a Census SLDU (upper chamber) or SLDL
+// (lower chamber) file.  The chamber is taken from the SLDUST or SLDLST
+// property of each feature, and the state from its STATEFP property.
+// Features for areas with no district, which the Census codes "ZZZ", are
+// skipped.
+func (s *StateDirectory) LoadDistricts(r io.Reader) error {
+	features, err := readGeoJSON(r)
+	if err != nil {
+		return err
+	}
+	for _, f := range features {
+		d := StateDistrict{Chamber: StateUpper, District: f.property("SLDUST")}
+		if d.District == "" {
+			d = StateDistrict{Chamber: StateLower, District: f.property("SLDLST")}
+		}
+		if d.District == "" {
+			return errors.New("GeoJSON feature has neither an SLDUST nor an SLDLST property")
+		}
+		if d.District == "ZZZ" {
+			continue
+		}
+		state := StateForFIPS(f.property("STATEFP", "STATEFP20", "STATEFP10"))
+		if state == nil {
+			return fmt.Errorf("Unknown state FIPS code for district %v", d.District)
+		}
+		d.State = state.Abbreviation
+		d.District = normalizeStateDistrict(d.District)
+		s.AddDistrict(d, f.Shape)
+	}
+	return nil
+}
+
+// LoadDistrictsFile reads state legislative district shapes from a
+// GeoJSON file on disk.
+func (s *StateDirectory) LoadDistrictsFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return s.LoadDistricts(f)
+}
+
+// StateLegislatorsFor returns the upper and lower chamber districts that
+// contain a location, and the legislators who represent them.  It returns
+// an error if the location is not in any loaded upper chamber district.
+func (s *StateDirectory) StateLegislatorsFor(latitude, longitude float64) (*StateRepresentation, error) {
+	p := Point{Longitude: longitude, Latitude: latitude}
+	r := &StateRepresentation{}
+	for _, d := range s.districts {
+		if !d.bounds.Intersects(Bounds{p, p}) || !d.shape.Contains(p) {
+			continue
+		}
+		district := d.district
+		switch district.Chamber {
+		case StateUpper:
+			r.Upper = &district
+		case StateLower:
+			r.Lower = &district
+		}
+	}
+	if r.Upper == nil {
+		return nil, fmt.Errorf("No state legislative district found for %v, %v", latitude, longitude)
+	}
+	r.UpperLegislators = s.Legislators(*r.Upper)
+	if r.Lower != nil {
+		r.LowerLegislators = s.Legislators(*r.Lower)
+	}
+	return r, nil
+}
+
+// StateLegislatorsForAddress geocodes an address and returns its state
+// legislators, along with the geocoded location.
+func (s *StateDirectory) StateLegislatorsForAddress(geocoder Geocoder, address string) (*StateRepresentation, *GeocodeResult, error) {
+	result, err := geocoder.Geocode(address)
+	if err != nil {
+		return nil, result, err
+	}
+	r, err := s.StateLegislatorsFor(result.Latitude, result.Longitude)
+	return r, result, err
+}
+
+func parseStateChamber(s string) (StateChamber, error) {
+	switch StateChamber(s) {
+	case StateUpper, StateLower:
+		return StateChamber(s), nil
+	case "legislature":
+		// Unicameral legislatures are counted as upper chambers, as the
+		// Census does for Nebraska and DC.
+		return StateUpper, nil
+	}
+	return "", fmt.Errorf("Unknown state legislative chamber %q", s)
+}
+
+// jurisdictionState returns the postal code of the state in an Open Civic
+// Data jurisdiction id, such as
+// "ocd-jurisdiction/country:us/state:ny/government".
+func jurisdictionState(jurisdiction string) (string, error) {
+	for _, part := range strings.Split(jurisdiction, "/") {
+		for _, prefix := range []string{"state:", "district:", "territory:"} {
+			if strings.HasPrefix(part, prefix) {
+				state := strings.ToUpper(strings.TrimPrefix(part, prefix))
+				if StateForAbbreviation(state) != nil {
+					return state, nil
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("No state in jurisdiction %q", jurisdiction)
+}
+
+// normalizeStateDistrict strips the leading zeros the Census uses in
+// numbered district codes, so "012" matches the Open States district "12".
+func normalizeStateDistrict(district string) string {
+	trimmed := strings.TrimLeft(district, "0")
+	if trimmed == "" {
+		return district
+	}
+	for _, c := range trimmed {
+		if c < '0' || c > '9' {
+			return district
+		}
+	}
+	return trimmed
+}
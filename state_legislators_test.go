@@ -0,0 +1,86 @@
+package gosunlight
+
+import (
+	"strings"
+	"testing"
+)
+
+// One upper chamber district covering two lower chamber districts, side
+// by side.
+const stateDistrictGrid = `{"type": "FeatureCollection", "features": [
+{"type": "Feature", "properties": {"STATEFP": "36", "SLDUST": "012"}, "geometry": {"type": "Polygon", "coordinates": [[[0,0],[2,0],[2,1],[0,1],[0,0]]]}},
+{"type": "Feature", "properties": {"STATEFP": "36", "SLDLST": "030"}, "geometry": {"type": "Polygon", "coordinates": [[[0,0],[1,0],[1,1],[0,1],[0,0]]]}},
+{"type": "Feature", "properties": {"STATEFP": "36", "SLDLST": "031"}, "geometry": {"type": "Polygon", "coordinates": [[[1,0],[2,0],[2,1],[1,1],[1,0]]]}},
+{"type": "Feature", "properties": {"STATEFP": "36", "SLDLST": "ZZZ"}, "geometry": {"type": "Polygon", "coordinates": [[[5,5],[6,5],[6,6],[5,6],[5,5]]]}}
+]}`
+
+const openStatesCSV = `id,name,current_party,current_district,current_chamber,given_name,family_name,email,capitol_voice
+ocd-person/1,Jane Senator,Democratic,12,upper,Jane,Senator,jane@example.com,518-555-0100
+ocd-person/2,John Member,Republican,30,lower,John,Member,,
+`
+
+const openStatesJSON = `[
+{"id": "ocd-person/3", "name": "Ann Member", "party": [{"name": "Republican", "end_date": "2019-01-01"}, {"name": "Democratic"}],
+ "roles": [{"type": "lower", "district": "30", "jurisdiction": "ocd-jurisdiction/country:us/state:ny/government", "end_date": "2018-12-31"},
+           {"type": "lower", "district": "31", "jurisdiction": "ocd-jurisdiction/country:us/state:ny/government"}],
+ "offices": [{"classification": "district", "address": "1 Main St", "voice": "212-555-0100"}]},
+{"id": "ocd-person/4", "name": "Former Member", "roles": [{"type": "upper", "district": "1", "jurisdiction": "ocd-jurisdiction/country:us/state:ny/government", "end_date": "2010-12-31"}]}
+]`
+
+func testStateDirectory(t *testing.T) *StateDirectory {
+	s := NewStateDirectory()
+	if err := s.LoadDistricts(strings.NewReader(stateDistrictGrid)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.LoadOpenStatesCSV("NY", strings.NewReader(openStatesCSV)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.LoadOpenStatesJSON(strings.NewReader(openStatesJSON)); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestStateLegislatorsFor(t *testing.T) {
+	s := testStateDirectory(t)
+	tests := []struct {
+		latitude, longitude float64
+		upper, lower        string
+	}{
+		{0.5, 0.5, "Jane Senator", "John Member"},
+		{0.5, 1.5, "Jane Senator", "Ann Member"},
+	}
+	for _, test := range tests {
+		r, err := s.StateLegislatorsFor(test.latitude, test.longitude)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Upper.String() != "NY upper 12" || len(r.UpperLegislators) != 1 || r.UpperLegislators[0].Name != test.upper {
+			t.Errorf("upper chamber = %v %v, want %v", r.Upper, r.UpperLegislators, test.upper)
+		}
+		if len(r.LowerLegislators) != 1 || r.LowerLegislators[0].Name != test.lower {
+			t.Errorf("lower chamber = %v %v, want %v", r.Lower, r.LowerLegislators, test.lower)
+		}
+		if len(r.Legislators()) != 2 {
+			t.Errorf("got %v legislators, want 2", len(r.Legislators()))
+		}
+	}
+	if _, err := s.StateLegislatorsFor(5.5, 5.5); err == nil {
+		t.Error("expected error outside every district")
+	}
+}
+
+func TestOpenStatesJSON(t *testing.T) {
+	s := testStateDirectory(t)
+	legislators := s.Legislators(StateDistrict{State: "NY", Chamber: StateLower, District: "31"})
+	if len(legislators) != 1 {
+		t.Fatalf("got %v legislators, want 1", len(legislators))
+	}
+	l := legislators[0]
+	if l.Party != "Democratic" || l.DistrictPhone != "212-555-0100" {
+		t.Errorf("unexpected legislator %+v", l)
+	}
+	if former := s.Legislators(StateDistrict{State: "NY", Chamber: StateUpper, District: "1"}); len(former) != 0 {
+		t.Errorf("former legislators loaded: %v", former)
+	}
+}
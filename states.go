@@ -0,0 +1,107 @@
+package gosunlight
+
+// State describes a state, DC, or territory that is represented in
+// Congress.
+type State struct {
+	Abbreviation string
+	Name         string
+	FIPS         string
+
+	// Territory is true for DC and the territories, which elect a
+	// non-voting member to the House and have no senators.
+	Territory bool
+}
+
+var states = []State{
+	{"AL", "Alabama", "01", false},
+	{"AK", "Alaska", "02", false},
+	{"AZ", "Arizona", "04", false},
+	{"AR", "Arkansas", "05", false},
+	{"CA", "California", "06", false},
+	{"CO", "Colorado", "08", false},
+	{"CT", "Connecticut", "09", false},
+	{"DE", "Delaware", "10", false},
+	{"DC", "District of Columbia", "11", true},
+	{"FL", "Florida", "12", false},
+	{"GA", "Georgia", "13", false},
+	{"HI", "Hawaii", "15", false},
+	{"ID", "Idaho", "16", false},
+	{"IL", "Illinois", "17", false},
+	{"IN", "Indiana", "18", false},
+	{"IA", "Iowa", "19", false},
+	{"KS", "Kansas", "20", false},
+	{"KY", "Kentucky", "21", false},
+	{"LA", "Louisiana", "22", false},
+	{"ME", "Maine", "23", false},
+	{"MD", "Maryland", "24", false},
+	{"MA", "Massachusetts", "25", false},
+	{"MI", "Michigan", "26", false},
+	{"MN", "Minnesota", "27", false},
+	{"MS", "Mississippi", "28", false},
+	{"MO", "Missouri", "29", false},
+	{"MT", "Montana", "30", false},
+	{"NE", "Nebraska", "31", false},
+	{"NV", "Nevada", "32", false},
+	{"NH", "New Hampshire", "33", false},
+	{"NJ", "New Jersey", "34", false},
+	{"NM", "New Mexico", "35", false},
+	{"NY", "New York", "36", false},
+	{"NC", "North Carolina", "37", false},
+	{"ND", "North Dakota", "38", false},
+	{"OH", "Ohio", "39", false},
+	{"OK", "Oklahoma", "40", false},
+	{"OR", "Oregon", "41", false},
+	{"PA", "Pennsylvania", "42", false},
+	{"RI", "Rhode Island", "44", false},
+	{"SC", "South Carolina", "45", false},
+	{"SD", "South Dakota", "46", false},
+	{"TN", "Tennessee", "47", false},
+	{"TX", "Texas", "48", false},
+	{"UT", "Utah", "49", false},
+	{"VT", "Vermont", "50", false},
+	{"VA", "Virginia", "51", false},
+	{"WA", "Washington", "53", false},
+	{"WV", "West Virginia", "54", false},
+	{"WI", "Wisconsin", "55", false},
+	{"WY", "Wyoming", "56", false},
+	{"AS", "American Samoa", "60", true},
+	{"GU", "Guam", "66", true},
+	{"MP", "Northern Mariana Islands", "69", true},
+	{"PR", "Puerto Rico", "72", true},
+	{"VI", "U.S. Virgin Islands", "78", true},
+}
+
+var statesByAbbreviation = make(map[string]*State)
+var statesByFIPS = make(map[string]*State)
+
+func init() {
+	for i := range states {
+		statesByAbbreviation[states[i].Abbreviation] = &states[i]
+		statesByFIPS[states[i].FIPS] = &states[i]
+	}
+}
+
+// atLargeStates are the states apportioned a single representative after
+// the 2010 census.
+var atLargeStates = map[string]bool{
+	"AK": true, "DE": true, "MT": true, "ND": true, "SD": true, "VT": true, "WY": true,
+}
+
+// SingleSeat reports whether the state elects a single member of the
+// House, either from an at-large district or, for DC and the territories,
+// as a non-voting member.
+func (s *State) SingleSeat() bool {
+	return s.Territory || atLargeStates[s.Abbreviation]
+}
+
+// StateForAbbreviation returns the state for a two letter postal code, or
+// nil if the code is unknown.
+func StateForAbbreviation(abbreviation string) *State {
+	return statesByAbbreviation[abbreviation]
+}
+
+// StateForFIPS returns the state for a two digit FIPS code, or nil if the
+// code is unknown.
+func StateForFIPS(fips string) *State {
+	return statesByFIPS[fips]
+}
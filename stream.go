@@ -0,0 +1,189 @@
+package gosunlight
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Lists are decoded an element at a time, rather than into nested response
+// structs, so that only one element is held at a time and consumers can
+// stop reading early.
+
+// decodeList decodes a list response of the form
+//
+//	{"response": {"<list>": [{"<item>": {...}}, ...]}}
+//
+// decoding each element of the list into an item type W, such as
+// legislatorItem, and passing it to fn.  The same W is reused for every
+// element, so fn must copy out what it keeps.  Other keys are skipped,
+// and keys are matched case insensitively, as encoding/json does.
+// Decoding stops without reading the rest of the response if fn returns
+// false.
+func decodeList[W any](decoder *json.Decoder, list string, fn func(*W) bool) error {
+	return decodeListInto(decoder, list, nil, fn)
+}
+
+// decodeListInto is decodeList, calling prepare, if set, on each cleared
+// item before the element is decoded into it.
+func decodeListInto[W any](decoder *json.Decoder, list string, prepare func(*W), fn func(*W) bool) error {
+	stopped := false
+	return walkObject(decoder, func(key string) (bool, error) {
+		if !strings.EqualFold(key, "response") {
+			return true, skipValue(decoder)
+		}
+		err := walkObject(decoder, func(key string) (bool, error) {
+			if !strings.EqualFold(key, list) {
+				return true, skipValue(decoder)
+			}
+			if err := expectDelim(decoder, '['); err != nil {
+				return false, err
+			}
+			var item, zero W
+			for decoder.More() {
+				item = zero
+				if prepare != nil {
+					prepare(&item)
+				}
+				if err := decoder.Decode(&item); err != nil {
+					return false, err
+				}
+				if !fn(&item) {
+					stopped = true
+					return false, nil
+				}
+			}
+			_, err := decoder.Token()
+			return err == nil, err
+		})
+		return err == nil && !stopped, err
+	})
+}
+
+// walkObject reads a JSON object, calling fn with each key.  fn must read
+// the key's value, and returns false to stop reading the object.  A null
+// value is treated as an empty object.
+func walkObject(decoder *json.Decoder, fn func(key string) (bool, error)) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return nil
+	}
+	if token != json.Delim('{') {
+		return fmt.Errorf("Expected a JSON object, got %v", token)
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		more, err := fn(token.(string))
+		if err != nil || !more {
+			return err
+		}
+	}
+	_, err = decoder.Token()
+	return err
+}
+
+// expectDelim reads a delimiter, returning an error if the next token is
+// anything else.
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("Expected %v in JSON, got %v", delim, token)
+	}
+	return nil
+}
+
+// skipValue reads and discards the next value.
+func skipValue(decoder *json.Decoder) error {
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// streamList requests a list endpoint and passes each item, converted
+// with convert, to fn as it is decoded.  It returns the number of items
+// decoded.
+func streamList[W, T any](ctx context.Context, api sunlightAPI, list string, convert func(*W) *T, fn func(*T) bool, params ...paramable) (int, error) {
+	n := 0
+	err := api.stream(ctx, func(decoder *json.Decoder) error {
+		return decodeList(decoder, list, func(w *W) bool {
+			n++
+			return fn(convert(w))
+		})
+	}, params...)
+	return n, err
+}
+
+// listItem is an item type whose element can be decoded into storage it
+// is given, rather than into one it allocates.
+type listItem[W, T any] interface {
+	*W
+	into(*T)
+}
+
+// maxBlock is the most elements allocated at once by collectList.
+const maxBlock = 256
+
+// collectList requests a list endpoint and returns its converted items.
+func collectList[W, T any, P listItem[W, T]](ctx context.Context, api sunlightAPI, list string, convert func(*W) *T, params ...paramable) ([]*T, error) {
+	var results []*T
+	err := api.stream(ctx, func(decoder *json.Decoder) error {
+		var err error
+		results, err = collectDecoded[W, T, P](decoder, list, convert)
+		return err
+	}, params...)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// collectDecoded decodes a list response into a slice of converted items.
+// Elements are decoded into blocks of storage, which double in size up to
+// maxBlock elements, rather than each into its own allocation.
+func collectDecoded[W, T any, P listItem[W, T]](decoder *json.Decoder, list string, convert func(*W) *T) ([]*T, error) {
+	results := []*T{}
+	var block []T
+	prepare := func(w *W) {
+		if len(block) == cap(block) {
+			block = make([]T, 0, min(max(2*cap(block), 4), maxBlock))
+		}
+		block = block[:len(block)+1]
+		P(w).into(&block[len(block)-1])
+	}
+	err := decodeListInto(decoder, list, prepare, func(w *W) bool {
+		results = append(results, convert(w))
+		return true
+	})
+	return results, err
+}
+
+// streamPages returns a page fetcher for paginate over a list endpoint.
+func streamPages[W, T any](api sunlightAPI, list string, convert func(*W) *T, params ...paramable) func(ctx context.Context, page int, yield func(*T) bool) (int, error) {
+	return func(ctx context.Context, page int, yield func(*T) bool) (int, error) {
+		p := append(append([]paramable(nil), params...), pageParams(page))
+		return streamList(ctx, api, list, convert, yield, p...)
+	}
+}
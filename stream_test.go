@@ -0,0 +1,122 @@
+package gosunlight
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDecodeList(t *testing.T) {
+	data := `{"Response": {"count": 3, "meta": {"page": [1, {"x": null}]},
+		"legislators": [
+			{"legislator": {"firstname": "Nancy", "bioguide_id": "P000197"}},
+			{"other": true, "legislator": {"firstname": "John", "bioguide_id": "B000589"}},
+			{"legislator": {"firstname": "Harry", "bioguide_id": "R000146"}}
+		]}, "trailing": "ignored"}`
+	var names []string
+	err := decodeList(json.NewDecoder(strings.NewReader(data)), "legislators", func(l *legislatorItem) bool {
+		names = append(names, l.Legislator.FirstName)
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(names, " ") != "Nancy John Harry" {
+		t.Errorf("decoded %v", names)
+	}
+
+	names = nil
+	err = decodeList(json.NewDecoder(strings.NewReader(data)), "legislators", func(l *legislatorItem) bool {
+		names = append(names, l.Legislator.FirstName)
+		return len(names) < 2
+	})
+	if err != nil || len(names) != 2 {
+		t.Errorf("stopping early decoded %v, %v", names, err)
+	}
+
+	err = decodeList(json.NewDecoder(strings.NewReader(`{"response": {"legislators": {}}}`)), "legislators", func(*legislatorItem) bool {
+		return true
+	})
+	if err == nil {
+		t.Error("expected an error for a malformed list")
+	}
+}
+
+// benchmarkLegislators is a legislators.getList response with n
+// legislators.
+func benchmarkLegislators(n int) []byte {
+	var b bytes.Buffer
+	b.WriteString(`{"response": {"legislators": [`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `{"legislator": {"title": "Rep", "firstname": "First%v", "lastname": "Last%v",
+			"party": "D", "state": "NY", "district": "%v", "in_office": true, "gender": "F",
+			"phone": "202-225-0000", "website": "http://example.house.gov", "bioguide_id": "X%06d",
+			"votesmart_id": "%v", "fec_id": "H0NY00000", "govtrack_id": "%v", "crp_id": "N00000000",
+			"twitter_id": "rep%v", "birthdate": "1960-01-01"}}`, i, i, i%30, i, i, i, i)
+	}
+	b.WriteString(`]}}`)
+	return b.Bytes()
+}
+
+// BenchmarkDecodeLegislatorsResponse decodes a large list the way lists
+// were decoded before streaming: into nested response structs, then
+// copied into a slice.
+func BenchmarkDecodeLegislatorsResponse(b *testing.B) {
+	data := benchmarkLegislators(2000)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var r struct {
+			Response struct {
+				Legislators []legislatorItem
+			}
+		}
+		if err := json.NewDecoder(bytes.NewReader(data)).Decode(&r); err != nil {
+			b.Fatal(err)
+		}
+		legislators := make([]*Legislator, 0, len(r.Response.Legislators))
+		for _, l := range r.Response.Legislators {
+			legislators = append(legislators, l.Legislator)
+		}
+		if len(legislators) != 2000 {
+			b.Fatal("wrong number of legislators")
+		}
+	}
+}
+
+// BenchmarkDecodeLegislatorsCollect streams a large list into a slice,
+// with legislators allocated in blocks, as LegislatorGetListAll does.
+func BenchmarkDecodeLegislatorsCollect(b *testing.B) {
+	data := benchmarkLegislators(2000)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		legislators, err := collectDecoded(json.NewDecoder(bytes.NewReader(data)), "legislators", (*legislatorItem).legislator)
+		if err != nil || len(legislators) != 2000 {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkDecodeLegislatorsStream streams a large list to a consumer that
+// keeps one legislator at a time, as the iterators do.
+func BenchmarkDecodeLegislatorsStream(b *testing.B) {
+	data := benchmarkLegislators(2000)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		n := 0
+		err := decodeList(json.NewDecoder(bytes.NewReader(data)), "legislators", func(l *legislatorItem) bool {
+			n++
+			return true
+		})
+		if err != nil || n != 2000 {
+			b.Fatal(err)
+		}
+	}
+}
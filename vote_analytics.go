@@ -0,0 +1,217 @@
+package gosunlight
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// VoteAnalytics computes voting metrics from a local set of votes with
+// their positions loaded, such as those returned by VoteGet, and the
+// legislators who cast them.  Each metric is computed over the votes taken
+// between from and to, inclusive; a zero time leaves that end of the range
+// open.  Along with each rate, metrics return the number of votes it was
+// computed from, and a rate of 0 when there were none.
+type VoteAnalytics struct {
+	votes       []*Vote
+	times       map[*Vote]time.Time
+	legislators map[string]*Legislator
+
+	// Leaders maps each party to the Bioguide ID of its leader, for
+	// LeadershipAgreement.
+	Leaders map[string]string
+}
+
+// NewVoteAnalytics returns analytics over a set of votes and legislators.
+func NewVoteAnalytics(votes []*Vote, legislators []*Legislator) *VoteAnalytics {
+	a := &VoteAnalytics{
+		votes:       append([]*Vote(nil), votes...),
+		times:       make(map[*Vote]time.Time, len(votes)),
+		legislators: make(map[string]*Legislator, len(legislators)),
+		Leaders:     make(map[string]string),
+	}
+	for _, v := range votes {
+		a.times[v], _ = v.Time()
+	}
+	sort.SliceStable(a.votes, func(i, j int) bool {
+		return a.times[a.votes[i]].Before(a.times[a.votes[j]])
+	})
+	for _, l := range legislators {
+		a.legislators[l.BioguideID] = l
+	}
+	return a
+}
+
+// each calls fn for every vote between from and to.
+func (a *VoteAnalytics) each(from, to time.Time, fn func(*Vote)) {
+	for _, v := range a.votes {
+		t := a.times[v]
+		if (!from.IsZero() && t.Before(from)) || (!to.IsZero() && t.After(to)) {
+			continue
+		}
+		fn(v)
+	}
+}
+
+// Agreement returns the share of votes on which two legislators took the
+// same side, counting only votes where both voted yes or no.
+func (a *VoteAnalytics) Agreement(first, second string, from, to time.Time) (float64, int) {
+	agree, shared := 0, 0
+	a.each(from, to, func(v *Vote) {
+		p, q := v.Position(first), v.Position(second)
+		if (p.Yes() || p.No()) && (q.Yes() || q.No()) {
+			shared++
+			if p.Yes() == q.Yes() {
+				agree++
+			}
+		}
+	})
+	return rate(agree, shared)
+}
+
+// MissedVotes returns the share of votes a legislator was eligible for but
+// did not vote on.
+func (a *VoteAnalytics) MissedVotes(id string, from, to time.Time) (float64, int) {
+	missed, eligible := 0, 0
+	a.each(from, to, func(v *Vote) {
+		if p, ok := v.Positions[id]; ok {
+			eligible++
+			if p == PositionNotVoting {
+				missed++
+			}
+		}
+	})
+	return rate(missed, eligible)
+}
+
+// PartyUnity returns the share of party unity votes on which a legislator
+// voted with the majority of their party.  A party unity vote is one where
+// a majority of voting Democrats opposed a majority of voting Republicans.
+// Party majorities are computed from the legislators given to
+// NewVoteAnalytics.
+func (a *VoteAnalytics) PartyUnity(id string, from, to time.Time) (float64, int) {
+	l, ok := a.legislators[id]
+	if !ok {
+		return 0, 0
+	}
+	with, total := 0, 0
+	a.each(from, to, func(v *Vote) {
+		majorities := a.partyMajorities(v)
+		dem, demOK := majorities["D"]
+		rep, repOK := majorities["R"]
+		if !demOK || !repOK || dem == rep {
+			return
+		}
+		party, ok := majorities[l.Party]
+		p := v.Position(id)
+		if !ok || !(p.Yes() || p.No()) {
+			return
+		}
+		total++
+		if p.Yes() == party {
+			with++
+		}
+	})
+	return rate(with, total)
+}
+
+// partyMajorities returns, for each party with a majority on a vote,
+// whether that majority voted yes.
+func (a *VoteAnalytics) partyMajorities(v *Vote) map[string]bool {
+	// margins counts yes votes minus no votes for each party.
+	margins := make(map[string]int)
+	for id, p := range v.Positions {
+		l, ok := a.legislators[id]
+		if !ok {
+			continue
+		}
+		if p.Yes() {
+			margins[l.Party]++
+		} else if p.No() {
+			margins[l.Party]--
+		}
+	}
+	majorities := make(map[string]bool)
+	for party, margin := range margins {
+		if margin != 0 {
+			majorities[party] = margin > 0
+		}
+	}
+	return majorities
+}
+
+// LeadershipAgreement returns the share of votes on which a legislator
+// agreed with their party's leader, as set in Leaders.
+func (a *VoteAnalytics) LeadershipAgreement(id string, from, to time.Time) (float64, int) {
+	l, ok := a.legislators[id]
+	if !ok {
+		return 0, 0
+	}
+	leader, ok := a.Leaders[l.Party]
+	if !ok {
+		return 0, 0
+	}
+	return a.Agreement(id, leader, from, to)
+}
+
+// WriteCSV writes a row of metrics for each legislator, ordered by
+// Bioguide ID.  Rates with no votes to compute them from are left blank.
+func (a *VoteAnalytics) WriteCSV(w io.Writer, from, to time.Time) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"bioguide_id", "name", "party", "state", "votes", "missed_votes", "party_unity", "leadership_agreement"})
+
+	ids := make([]string, 0, len(a.legislators))
+	for id := range a.legislators {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		l := a.legislators[id]
+		missed, eligible := a.MissedVotes(id, from, to)
+		unity, unityVotes := a.PartyUnity(id, from, to)
+		leadership, leadershipVotes := a.LeadershipAgreement(id, from, to)
+		writer.Write([]string{
+			id,
+			l.FirstName + " " + l.LastName,
+			l.Party,
+			l.State,
+			strconv.Itoa(eligible),
+			formatRate(missed, eligible),
+			formatRate(unity, unityVotes),
+			formatRate(leadership, leadershipVotes),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteAgreementCSV writes the pairwise agreement between each pair of
+// the given legislators.
+func (a *VoteAnalytics) WriteAgreementCSV(w io.Writer, ids []string, from, to time.Time) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"bioguide_id", "other_bioguide_id", "agreement", "shared_votes"})
+	for i, first := range ids {
+		for _, second := range ids[i+1:] {
+			agreement, shared := a.Agreement(first, second, from, to)
+			writer.Write([]string{first, second, formatRate(agreement, shared), strconv.Itoa(shared)})
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func rate(count, total int) (float64, int) {
+	if total == 0 {
+		return 0, 0
+	}
+	return float64(count) / float64(total), total
+}
+
+func formatRate(r float64, n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(r, 'f', 4, 64)
+}
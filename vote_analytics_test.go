@@ -0,0 +1,75 @@
+package gosunlight
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testVoteAnalytics() *VoteAnalytics {
+	legislators := []*Legislator{
+		{BioguideID: "D1", FirstName: "Dana", LastName: "One", Party: "D", State: "CA"},
+		{BioguideID: "D2", FirstName: "Dee", LastName: "Two", Party: "D", State: "NY"},
+		{BioguideID: "D3", FirstName: "Don", LastName: "Three", Party: "D", State: "IL"},
+		{BioguideID: "R1", FirstName: "Ron", LastName: "One", Party: "R", State: "TX"},
+		{BioguideID: "R2", FirstName: "Rae", LastName: "Two", Party: "R", State: "OH"},
+	}
+	votes := []*Vote{
+		{VotedAt: "2013-01-10T12:00:00Z", Positions: map[string]Position{
+			"D1": "Yea", "D2": "Yea", "D3": "Nay", "R1": "Nay", "R2": "Nay"}},
+		{VotedAt: "2013-01-03T12:00:00Z", Positions: map[string]Position{
+			"D1": "Yea", "D2": "Yea", "D3": "Yea", "R1": "Yea", "R2": "Not Voting"}},
+		{VotedAt: "2013-02-01T12:00:00Z", Positions: map[string]Position{
+			"D1": "Aye", "D2": "No", "D3": "Aye", "R1": "No", "R2": "No"}},
+	}
+	a := NewVoteAnalytics(votes, legislators)
+	a.Leaders["D"] = "D1"
+	a.Leaders["R"] = "R1"
+	return a
+}
+
+func TestVoteAnalytics(t *testing.T) {
+	a := testVoteAnalytics()
+	var none time.Time
+
+	if agreement, n := a.Agreement("D1", "D3", none, none); n != 3 || agreement != 2.0/3 {
+		t.Errorf("Agreement(D1, D3) = %v over %v", agreement, n)
+	}
+	if missed, n := a.MissedVotes("R2", none, none); n != 3 || missed != 1.0/3 {
+		t.Errorf("MissedVotes(R2) = %v over %v", missed, n)
+	}
+	// Votes 1 and 3 are party unity votes; D3 broke with the party on 1.
+	if unity, n := a.PartyUnity("D3", none, none); n != 2 || unity != .5 {
+		t.Errorf("PartyUnity(D3) = %v over %v", unity, n)
+	}
+	if leadership, n := a.LeadershipAgreement("D2", none, none); n != 3 || leadership != 2.0/3 {
+		t.Errorf("LeadershipAgreement(D2) = %v over %v", leadership, n)
+	}
+
+	from := time.Date(2013, 1, 5, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2013, 1, 31, 0, 0, 0, 0, time.UTC)
+	if agreement, n := a.Agreement("D1", "D2", from, to); n != 1 || agreement != 1 {
+		t.Errorf("Agreement(D1, D2) in January = %v over %v", agreement, n)
+	}
+}
+
+func TestVoteAnalyticsCSV(t *testing.T) {
+	a := testVoteAnalytics()
+	var out bytes.Buffer
+	if err := a.WriteCSV(&out, time.Time{}, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 6 || lines[5] != "R2,Rae Two,R,OH,3,0.3333,1.0000,1.0000" {
+		t.Errorf("unexpected CSV:\n%v", out.String())
+	}
+
+	out.Reset()
+	if err := a.WriteAgreementCSV(&out, []string{"D1", "R1"}, time.Time{}, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "D1,R1,0.3333,3") {
+		t.Errorf("unexpected agreement CSV:\n%v", out.String())
+	}
+}
@@ -0,0 +1,195 @@
+package gosunlight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var voteAPIS struct {
+	get     sunlightAPI
+	getList sunlightAPI
+}
+
+func init() {
+	voteAPIS.get = newSunlightAPI("votes", "get")
+	voteAPIS.getList = newSunlightAPI("votes", "getList")
+}
+
+// Position is how a legislator voted on a roll call.
+type Position string
+
+const (
+	PositionYea       Position = "Yea"
+	PositionNay       Position = "Nay"
+	PositionPresent   Position = "Present"
+	PositionNotVoting Position = "Not Voting"
+)
+
+// Yes reports whether the position is a vote in favor.  The House records
+// these as "Aye" on some questions.
+func (p Position) Yes() bool {
+	return p == PositionYea || p == "Aye" || p == "Yes"
+}
+
+// No reports whether the position is a vote against.  The House records
+// these as "No" on some questions.
+func (p Position) No() bool {
+	return p == PositionNay || p == "No"
+}
+
+// Vote represents a single roll call vote from the sunlight api.
+type Vote struct {
+	RollID    string        `json:"roll_id"`
+	Chamber   string        `json:"chamber"`
+	Congress  int           `json:"congress"`
+	Number    int           `json:"number"`
+	Year      int           `json:"year"`
+	Question  string        `json:"question"`
+	Result    string        `json:"result"`
+	Required  string        `json:"required"`
+	VoteType  string        `json:"vote_type"`
+	VotedAt   string        `json:"voted_at"`
+	BillID    string        `json:"bill_id"`
+	Breakdown VoteBreakdown `json:"breakdown"`
+
+	// Positions maps the Bioguide ID of each legislator to how they voted.
+	Positions map[string]Position `json:"voter_ids"`
+}
+
+// VoteBreakdown counts the positions taken on a vote, in total and by
+// party.
+type VoteBreakdown struct {
+	Total map[Position]int            `json:"total"`
+	Party map[string]map[Position]int `json:"party"`
+}
+
+// String implements fmt.Stringer for votes
+func (v Vote) String() string {
+	return fmt.Sprintf("%v %v (%v)", v.RollID, v.Question, v.Result)
+}
+
+// Time returns the time the vote was taken.
+func (v Vote) Time() (time.Time, error) {
+	return time.Parse(time.RFC3339, v.VotedAt)
+}
+
+// Position returns how a legislator voted, or "" if they were not eligible
+// to vote or positions were not loaded.
+func (v Vote) Position(bioguideID string) Position {
+	return v.Positions[bioguideID]
+}
+
+// VoteFilter limits the votes returned by VoteGetList.  Fields left empty
+// are not used.
+type VoteFilter struct {
+	Chamber  string
+	Congress int
+	BillID   string
+
+	// VoterID is the Bioguide ID of a legislator who voted.
+	VoterID string
+
+	// Since and Until limit votes to a range of dates, formatted as
+	// YYYY-MM-DD.
+	Since string
+	Until string
+}
+
+// Implementation of paramable for vote filters
+func (f VoteFilter) addTo(query *url.Values) {
+	if f.Chamber != "" {
+		query.Add("chamber", f.Chamber)
+	}
+	if f.Congress != 0 {
+		query.Add("congress", strconv.Itoa(f.Congress))
+	}
+	if f.BillID != "" {
+		query.Add("bill_id", f.BillID)
+	}
+	if f.VoterID != "" {
+		query.Add("voter_ids."+f.VoterID+"__exists", "true")
+	}
+	if f.Since != "" {
+		query.Add("voted_at__gte", f.Since)
+	}
+	if f.Until != "" {
+		query.Add("voted_at__lte", f.Until)
+	}
+}
+
+// VoteGet returns a single roll call vote, with the position of every
+// legislator, given a roll id such as "h7-2013".
+//
+// See: http://services.sunlightlabs.com/docs/congressapi/votes.get/
+func VoteGet(rollID string) (*Vote, error) {
+	var response voteResponse
+	p := params{"roll_id": rollID}
+	err := voteAPIS.get.get(&response, p)
+	if err != nil {
+		return nil, err
+	}
+	if response.Response.Vote == nil {
+		return nil, fmt.Errorf("Vote %v not found", rollID)
+	}
+	return response.Response.Vote, nil
+}
+
+// VoteGetList returns the first page of roll call votes matching a filter.
+// Use VoteGetListIter for every page.
+//
+// See: http://services.sunlightlabs.com/docs/congressapi/votes.getList/
+func VoteGetList(filter VoteFilter) ([]*Vote, error) {
+	return collectList(context.Background(), voteAPIS.getList, "votes", (*voteItem).vote, filter)
+}
+
+// MemberVote is a legislator's position on a single vote.
+type MemberVote struct {
+	Vote     *Vote
+	Position Position
+}
+
+// Votes returns this legislator's voting record: every vote matching the
+// filter that they were eligible for, and how they voted.  The filter's
+// VoterID is ignored.  The first call for a filter will block while each
+// page of votes is fetched from sunlight, so narrow the filter to limit
+// the requests made.  Subsequent calls return a cached list.
+func (l *Legislator) Votes(filter VoteFilter) ([]*MemberVote, error) {
+	if l.BioguideID == "" {
+		return nil, errors.New("BioguideId missing for legislator.")
+	}
+	filter.VoterID = l.BioguideID
+	return legislatorVotes.get(filter, func() ([]*MemberVote, error) {
+		votes, err := collect(VoteGetListIter(context.Background(), filter))
+		if err != nil {
+			return nil, err
+		}
+		record := make([]*MemberVote, 0, len(votes))
+		for _, v := range votes {
+			record = append(record, &MemberVote{Vote: v, Position: v.Position(filter.VoterID)})
+		}
+		return record, nil
+	})
+}
+
+type voteResponse struct {
+	Response struct {
+		Vote *Vote
+	}
+}
+
+// voteItem is an element of a list of votes.
+type voteItem struct {
+	Vote *Vote
+}
+
+func (i *voteItem) vote() *Vote {
+	return i.Vote
+}
+
+func (i *voteItem) into(v *Vote) {
+	i.Vote = v
+}
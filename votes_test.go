@@ -0,0 +1,61 @@
+package gosunlight
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const voteJSON = `{"response": {"vote": {
+	"roll_id": "h7-2013", "chamber": "house", "congress": 113, "number": 7, "year": 2013,
+	"question": "On Passage", "result": "Passed", "voted_at": "2013-01-04T16:22:00Z",
+	"breakdown": {"total": {"Yea": 2, "Nay": 1}, "party": {"R": {"Yea": 1, "Nay": 1}, "D": {"Yea": 1}}},
+	"voter_ids": {"B000589": "Yea", "P000197": "Yea", "K000210": "Nay", "G000289": "Not Voting"}
+}}}`
+
+func TestVoteDecoding(t *testing.T) {
+	var response voteResponse
+	if err := json.Unmarshal([]byte(voteJSON), &response); err != nil {
+		t.Fatal(err)
+	}
+	v := response.Response.Vote
+	if v.Position("K000210") != PositionNay || !v.Position("P000197").Yes() || v.Position("X000000") != "" {
+		t.Errorf("unexpected positions %v", v.Positions)
+	}
+	if v.Breakdown.Party["R"][PositionNay] != 1 || v.Breakdown.Total[PositionYea] != 2 {
+		t.Errorf("unexpected breakdown %+v", v.Breakdown)
+	}
+	if when, err := v.Time(); err != nil || when.Year() != 2013 {
+		t.Errorf("Time() = %v, %v", when, err)
+	}
+}
+
+func TestVoteFilter(t *testing.T) {
+	query := url.Values{}
+	VoteFilter{VoterID: "P000197", Since: "2013-01-01"}.addTo(&query)
+	if query.Encode() != "voted_at__gte=2013-01-01&voter_ids.P000197__exists=true" {
+		t.Errorf("unexpected query %v", query.Encode())
+	}
+}
+
+func TestLegislatorVotesPages(t *testing.T) {
+	withPerPage(t, 2)
+	serve(t, func(req *Request) (string, error) {
+		page, _ := strconv.Atoi(req.Params.Get("page"))
+		var items []string
+		for i := (page - 1) * PerPage; i < page*PerPage && i < 3; i++ {
+			items = append(items, fmt.Sprintf(`{"vote": {"roll_id": "h%v-2013", "voter_ids": {"P000197": "Yea"}}}`, i))
+		}
+		return `{"response": {"votes": [` + strings.Join(items, ",") + `]}}`, nil
+	})
+	record, err := (&Legislator{BioguideID: "P000197"}).Votes(VoteFilter{Congress: 113})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(record) != 3 || record[2].Vote.RollID != "h2-2013" || record[2].Position != PositionYea {
+		t.Errorf("got %v votes, want all 3", len(record))
+	}
+}